@@ -0,0 +1,309 @@
+package gecs
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// componentID is a dense world-local identifier of a component type.
+type componentID = int
+
+// archetype stores all entities that have exactly the same set of components.
+// Components are stored in columns, one column per component type, rows are entities.
+type archetype struct {
+	key string
+	ids []componentID // sorted
+
+	// columnIndex maps componentID of the component type to column index + 1, zero means that there is no such column.
+	columnIndex []int
+	// pairColumns maps componentID of the pair to column index. The pair ids are sparse, see world.newPairID.
+	pairColumns map[componentID]int
+	columns     [][]Component // [column][row]
+	entities    []*entity     // [row]
+
+	// Ticks of the last component addition and change.
+	added   [][]uint64 // [column][row]
+	changed [][]uint64 // [column][row]
+
+	// Cached transitions to the archetypes with one component added or removed.
+	edgesAdd    map[componentID]*archetype
+	edgesRemove map[componentID]*archetype
+
+	// dropped is set when the archetype with the pairs of a destroyed target is removed from the world.
+	dropped bool
+}
+
+func newArchetype(ids []componentID) *archetype {
+	a := &archetype{
+		key:         archetypeKey(ids),
+		ids:         ids,
+		columns:     make([][]Component, len(ids)),
+		added:       make([][]uint64, len(ids)),
+		changed:     make([][]uint64, len(ids)),
+		edgesAdd:    make(map[componentID]*archetype),
+		edgesRemove: make(map[componentID]*archetype),
+	}
+
+	for i, id := range ids {
+		if id < 0 {
+			if a.pairColumns == nil {
+				a.pairColumns = make(map[componentID]int)
+			}
+			a.pairColumns[id] = i
+			continue
+		}
+
+		for len(a.columnIndex) <= id {
+			a.columnIndex = append(a.columnIndex, 0)
+		}
+		a.columnIndex[id] = i + 1
+	}
+
+	return a
+}
+
+// archetypeKey returns a unique key for the sorted set of component ids.
+func archetypeKey(ids []componentID) string {
+	var b strings.Builder
+	for i, id := range ids {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.Itoa(id))
+	}
+	return b.String()
+}
+
+// column returns the column index of the component, or -1 if the archetype doesn't contain it.
+func (a *archetype) column(id componentID) int {
+	if id < 0 {
+		if col, ok := a.pairColumns[id]; ok {
+			return col
+		}
+
+		return -1
+	}
+
+	if id >= len(a.columnIndex) {
+		return -1
+	}
+
+	return a.columnIndex[id] - 1
+}
+
+func (a *archetype) has(id componentID) bool {
+	return a.column(id) >= 0
+}
+
+// appendEntity adds a row with empty components and returns its index.
+func (a *archetype) appendEntity(e *entity) int {
+	a.entities = append(a.entities, e)
+	for i := range a.columns {
+		a.columns[i] = append(a.columns[i], nil)
+		a.added[i] = append(a.added[i], 0)
+		a.changed[i] = append(a.changed[i], 0)
+	}
+
+	return len(a.entities) - 1
+}
+
+// removeRow removes the row by moving the last row in its place.
+func (a *archetype) removeRow(row int) {
+	last := len(a.entities) - 1
+
+	if row != last {
+		moved := a.entities[last]
+		a.entities[row] = moved
+		moved.row = row
+
+		for i := range a.columns {
+			a.columns[i][row] = a.columns[i][last]
+			a.added[i][row] = a.added[i][last]
+			a.changed[i][row] = a.changed[i][last]
+		}
+	}
+
+	a.entities[last] = nil
+	a.entities = a.entities[:last]
+	for i := range a.columns {
+		a.columns[i][last] = nil
+		a.columns[i] = a.columns[i][:last]
+		a.added[i] = a.added[i][:last]
+		a.changed[i] = a.changed[i][:last]
+	}
+}
+
+// set sets the component to the row and marks it as changed, and as added if it is new.
+func (a *archetype) set(col, row int, c Component, tick uint64, added bool) {
+	a.columns[col][row] = c
+	a.changed[col][row] = tick
+	if added {
+		a.added[col][row] = tick
+	}
+}
+
+// componentKey identifies a component type or a relationship pair.
+type componentKey struct {
+	t      componentType // the component or relation type, nil for any relation of the pair wildcard
+	target uint64        // the pair target entity ID, 0 for any target of the pair wildcard
+	pair   bool
+}
+
+// wildcard returns true if the key is a pair wildcard, which has no column and matches other pairs.
+func (k componentKey) wildcard() bool {
+	return k.pair && (k.t == nil || k.target == 0)
+}
+
+// matches returns true if the key is equal to the other one or the wildcard matching it.
+func (k componentKey) matches(other componentKey) bool {
+	if !k.wildcard() {
+		return k == other
+	}
+
+	return other.pair && !other.wildcard() && (k.t == nil || k.t == other.t) && (k.target == 0 || k.target == other.target)
+}
+
+// componentTypeID returns the id of the component type, registering it if necessary.
+func (w *world) componentTypeID(ct componentType) componentID {
+	return w.componentKeyID(componentKey{t: ct})
+}
+
+// componentKeyID returns the id of the component key, registering it if necessary.
+func (w *world) componentKeyID(k componentKey) componentID {
+	id, ok := w.componentIDs[k]
+	if ok {
+		return id
+	}
+
+	if k.pair {
+		return w.newPairID(k)
+	}
+
+	id = len(w.componentKeys)
+	w.componentIDs[k] = id
+	w.componentKeys = append(w.componentKeys, k)
+	return id
+}
+
+// componentKey returns the key of the component id.
+func (w *world) componentKey(id componentID) componentKey {
+	switch {
+	case id >= 0:
+		return w.componentKeys[id]
+	case id == noComponent:
+		return componentKey{}
+	default:
+		return w.pairKeys[-id-1]
+	}
+}
+
+// archetypeHas returns true if the archetype contains the component, or any pair matching the wildcard.
+func (w *world) archetypeHas(a *archetype, id componentID) bool {
+	return w.archetypeColumn(a, id) >= 0
+}
+
+// archetypeColumn returns the column index of the component, or of the first pair matching the wildcard,
+// or -1 if the archetype doesn't contain it.
+func (w *world) archetypeColumn(a *archetype, id componentID) int {
+	k := w.componentKey(id)
+	if !k.wildcard() {
+		return a.column(id)
+	}
+
+	for i, aid := range a.ids {
+		if k.matches(w.componentKey(aid)) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// archetypeByIDs returns the archetype for the sorted set of component ids, creating it if necessary.
+func (w *world) archetypeByIDs(ids []componentID) *archetype {
+	key := archetypeKey(ids)
+
+	a, ok := w.archetypeIndex[key]
+	if ok {
+		return a
+	}
+
+	a = newArchetype(ids)
+	w.archetypeIndex[key] = a
+	w.archetypes = append(w.archetypes, a)
+	w.indexPairArchetype(a)
+	w.filtersAddArchetype(a)
+	return a
+}
+
+// archetypeWith returns the archetype that contains all components of the passed one and the component id.
+func (w *world) archetypeWith(a *archetype, id componentID) *archetype {
+	if next, ok := a.edgesAdd[id]; ok {
+		return next
+	}
+
+	ids := make([]componentID, 0, len(a.ids)+1)
+	ids = append(ids, a.ids...)
+	ids = append(ids, id)
+	sort.Ints(ids)
+
+	next := w.archetypeByIDs(ids)
+	a.edgesAdd[id] = next
+	next.edgesRemove[id] = a
+	return next
+}
+
+// archetypeWithout returns the archetype that contains all components of the passed one except the component id.
+func (w *world) archetypeWithout(a *archetype, id componentID) *archetype {
+	if prev, ok := a.edgesRemove[id]; ok {
+		return prev
+	}
+
+	ids := make([]componentID, 0, len(a.ids))
+	for _, aid := range a.ids {
+		if aid != id {
+			ids = append(ids, aid)
+		}
+	}
+
+	prev := w.archetypeByIDs(ids)
+	a.edgesRemove[id] = prev
+	prev.edgesAdd[id] = a
+	return prev
+}
+
+// moveEntity moves the entity with all its components, which exist in the target archetype, to the target archetype.
+func (w *world) moveEntity(e *entity, to *archetype) {
+	from := e.archetype
+	row := to.appendEntity(e)
+
+	if from != nil {
+		for i, id := range from.ids {
+			col := to.column(id)
+			if col < 0 {
+				continue
+			}
+
+			to.columns[col][row] = from.columns[i][e.row]
+			to.added[col][row] = from.added[i][e.row]
+			to.changed[col][row] = from.changed[i][e.row]
+		}
+
+		from.removeRow(e.row)
+	}
+
+	e.archetype = to
+	e.row = row
+}
+
+// removeEntity removes the entity with all its components from its archetype.
+func (w *world) removeEntity(e *entity) {
+	if e.archetype == nil {
+		return
+	}
+
+	e.archetype.removeRow(e.row)
+	e.archetype = nil
+	e.row = 0
+}
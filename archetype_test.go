@@ -0,0 +1,81 @@
+package gecs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type Component3 struct {
+	Flag bool
+}
+
+func TestArchetype_Move(t *testing.T) {
+	w := NewWorld().(*world)
+
+	e1 := w.NewEntity()
+	e1.Replace(&Component1{Num: 1})
+	e1.Replace(&Component2{Text: "1"})
+
+	e2 := w.NewEntity()
+	e2.Replace(&Component2{Text: "2"})
+	e2.Replace(&Component1{Num: 2})
+
+	t.Run("Same component set shares archetype", func(t *testing.T) {
+		require.Same(t, e1.(*entity).archetype, e2.(*entity).archetype)
+		require.Len(t, e1.(*entity).archetype.entities, 2)
+	})
+
+	t.Run("Move keeps other rows consistent", func(t *testing.T) {
+		e1.Delete((*Component2)(nil))
+
+		require.NotSame(t, e1.(*entity).archetype, e2.(*entity).archetype)
+		require.Equal(t, 1, Get[*Component1](e1).Num)
+		require.Equal(t, 2, Get[*Component1](e2).Num)
+		require.Equal(t, "2", Get[*Component2](e2).Text)
+	})
+
+	t.Run("Move back reuses archetype", func(t *testing.T) {
+		e1.Replace(&Component2{Text: "1"})
+
+		require.Same(t, e1.(*entity).archetype, e2.(*entity).archetype)
+		require.Equal(t, "1", Get[*Component2](e1).Text)
+		require.Equal(t, "2", Get[*Component2](e2).Text)
+	})
+
+	t.Run("Destroy removes row", func(t *testing.T) {
+		a := e1.(*entity).archetype
+		e1.Destroy()
+
+		require.Nil(t, e1.(*entity).archetype)
+		require.Len(t, a.entities, 1)
+		require.Equal(t, e2.ID(), a.entities[0].ID())
+		require.Equal(t, 0, e2.(*entity).row)
+		require.Equal(t, 2, Get[*Component1](e2).Num)
+	})
+}
+
+func TestArchetype_FilterMatch(t *testing.T) {
+	w := NewWorld().(*world)
+	s := &Component1System{}
+	w.AddSystem(s)
+
+	f := w.systems[0].filters[0]
+	require.Len(t, f.archetypes, 0)
+
+	e := w.NewEntity()
+	e.Replace(&Component1{Num: 1})
+	require.Len(t, f.archetypes, 1, "New archetype with Component1 should be matched")
+
+	e.Replace(&Component3{Flag: true})
+	require.Len(t, f.archetypes, 2, "New archetype with Component1 and Component3 should be matched")
+
+	e.Replace(&Component2{Text: "excluded"})
+	require.Len(t, f.archetypes, 2, "Archetype with excluded Component2 should not be matched")
+
+	require.Len(t, f.entityList(0), 0)
+
+	e.Delete((*Component2)(nil))
+	require.Len(t, f.archetypes, 2)
+	require.Len(t, f.entityList(0), 1)
+}
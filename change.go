@@ -0,0 +1,77 @@
+package gecs
+
+// Change detection.
+//
+// The world has a tick counter, which is increased after every system update.
+// Components store the ticks of their addition and last change, the Delete calls are recorded in the removal log.
+// Destroy is not recorded, the destroyed entities are reported only to the OnRemove observers.
+// A system sees the changes made after its previous update, except its own changes.
+
+// removedComponent is the removal log record.
+type removedComponent struct {
+	e    *entity
+	tick uint64
+}
+
+// recordRemoved adds the component removal to the log, if any filter tracks the component removal.
+func (w *world) recordRemoved(e *entity, id componentID) {
+	if !w.removedTracked[id] {
+		return
+	}
+
+	w.removed[id] = append(w.removed[id], removedComponent{e: e, tick: w.tick})
+}
+
+// removedSince returns true if the component was removed from the entity after the tick.
+func (w *world) removedSince(e *entity, id componentID, since uint64) bool {
+	rs := w.removed[id]
+	for i := len(rs) - 1; i >= 0 && rs[i].tick > since; i-- {
+		if rs[i].e == e {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pruneRemoved drops the removal log records, which have been seen by all systems and queries.
+func (w *world) pruneRemoved() {
+	w.lock()
+	defer w.unlock()
+
+	if len(w.removed) == 0 {
+		return
+	}
+
+	oldest := w.tick
+	for _, e := range w.systems {
+		if e.disabled {
+			continue
+		}
+
+		for _, f := range e.filters {
+			if len(f.removed) > 0 && e.tick < oldest {
+				oldest = e.tick
+			}
+		}
+	}
+	for _, q := range w.queries {
+		if len(q.f.removed) > 0 && q.lastTick < oldest {
+			oldest = q.lastTick
+		}
+	}
+
+	for id, rs := range w.removed {
+		n := 0
+		for n < len(rs) && rs[n].tick <= oldest {
+			n++
+		}
+
+		if n == len(rs) {
+			delete(w.removed, id)
+			continue
+		}
+
+		w.removed[id] = append(rs[:0], rs[n:]...)
+	}
+}
@@ -0,0 +1,201 @@
+package gecs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChange_Added(t *testing.T) {
+	w := NewWorld()
+	s := &ChangeSystem{Filter: SystemFilter{Added: []Component{(*Component1)(nil)}}}
+	w.AddSystem(s)
+
+	e1 := w.NewEntity()
+	e1.Replace(&Component1{Num: 1})
+
+	w.SystemsUpdate(time.Second)
+	require.Equal(t, []Entity{e1}, s.Filtered)
+
+	t.Run("Not added again", func(t *testing.T) {
+		e1.Replace(&Component1{Num: 2})
+
+		w.SystemsUpdate(time.Second)
+		require.Len(t, s.Filtered, 0)
+	})
+
+	t.Run("Added after delete", func(t *testing.T) {
+		e1.Delete((*Component1)(nil))
+		e1.Replace(&Component1{Num: 3})
+
+		e2 := w.NewEntity()
+		e2.Get(&Component1{Num: 4})
+
+		w.SystemsUpdate(time.Second)
+		require.ElementsMatch(t, []Entity{e1, e2}, s.Filtered)
+	})
+
+	t.Run("Moving to another archetype keeps tick", func(t *testing.T) {
+		e1.Replace(&Component2{})
+
+		w.SystemsUpdate(time.Second)
+		require.Len(t, s.Filtered, 0)
+	})
+}
+
+func TestChange_Changed(t *testing.T) {
+	w := NewWorld()
+	s := &ChangeSystem{Filter: SystemFilter{
+		Include: []Component{(*Component2)(nil)},
+		Changed: []Component{(*Component1)(nil)},
+	}}
+	w.AddSystem(s)
+
+	e1 := w.NewEntity()
+	e1.Replace(&Component1{Num: 1})
+	e1.Replace(&Component2{})
+
+	e2 := w.NewEntity()
+	e2.Replace(&Component1{Num: 2})
+	e2.Replace(&Component2{})
+
+	w.SystemsUpdate(time.Second)
+	require.ElementsMatch(t, []Entity{e1, e2}, s.Filtered, "Added components are changed")
+
+	w.SystemsUpdate(time.Second)
+	require.Len(t, s.Filtered, 0)
+
+	t.Run("Replace", func(t *testing.T) {
+		e1.Replace(&Component1{Num: 10})
+
+		w.SystemsUpdate(time.Second)
+		require.Equal(t, []Entity{e1}, s.Filtered)
+	})
+
+	t.Run("Get doesn't change", func(t *testing.T) {
+		Get[*Component1](e2).Num++
+
+		w.SystemsUpdate(time.Second)
+		require.Len(t, s.Filtered, 0)
+	})
+
+	t.Run("MarkChanged", func(t *testing.T) {
+		e2.MarkChanged((*Component1)(nil))
+		e2.MarkChanged((*Component3)(nil)) // not exist
+
+		w.SystemsUpdate(time.Second)
+		require.Equal(t, []Entity{e2}, s.Filtered)
+	})
+}
+
+func TestChange_OwnChangesNotSeen(t *testing.T) {
+	w := NewWorld()
+	s := &ChangeSystem{
+		Filter: SystemFilter{Changed: []Component{(*Component1)(nil)}},
+		OnUpdate: func(filtered []Entity) {
+			for _, e := range filtered {
+				e.MarkChanged((*Component1)(nil))
+			}
+		},
+	}
+	w.AddSystem(s)
+
+	// The system after the changing one sees the changes.
+	next := &OtherChangeSystem{ChangeSystem{Filter: SystemFilter{Changed: []Component{(*Component1)(nil)}}}}
+	w.AddSystem(next)
+
+	e := w.NewEntity()
+	e.Replace(&Component1{})
+
+	w.SystemsUpdate(time.Second)
+	require.Equal(t, []Entity{e}, s.Filtered)
+	require.Equal(t, []Entity{e}, next.Filtered)
+
+	// The change made by the system in the previous update has already been seen by the next system.
+	w.SystemsUpdate(time.Second)
+	require.Len(t, s.Filtered, 0)
+	require.Len(t, next.Filtered, 0)
+
+	e.MarkChanged((*Component1)(nil))
+
+	w.SystemsUpdate(time.Second)
+	require.Equal(t, []Entity{e}, s.Filtered)
+	require.Equal(t, []Entity{e}, next.Filtered)
+}
+
+func TestChange_Removed(t *testing.T) {
+	w := NewWorld()
+	s := &ChangeSystem{Filter: SystemFilter{
+		Removed: []Component{(*Component1)(nil)},
+		Exclude: []Component{(*Component3)(nil)},
+	}}
+	w.AddSystem(s)
+
+	e1 := w.NewEntity()
+	e1.Replace(&Component1{})
+	e1.Replace(&Component2{})
+
+	e2 := w.NewEntity()
+	e2.Replace(&Component1{})
+	e2.Replace(&Component3{})
+
+	e3 := w.NewEntity()
+	e3.Replace(&Component1{})
+
+	w.SystemsUpdate(time.Second)
+	require.Len(t, s.Filtered, 0)
+
+	e1.Delete((*Component1)(nil))
+	e2.Delete((*Component1)(nil))
+	e3.Delete((*Component1)(nil))
+	e3.Destroy()
+
+	w.SystemsUpdate(time.Second)
+	require.Equal(t, []Entity{e1}, s.Filtered, "Excluded and destroyed entities should be skipped")
+
+	w.SystemsUpdate(time.Second)
+	require.Len(t, s.Filtered, 0)
+	require.Len(t, w.(*world).removed, 0, "Seen removals should be pruned")
+}
+
+func TestChange_Query(t *testing.T) {
+	w := NewWorld()
+	q := w.Query(SystemFilter{Changed: []Component{(*Component1)(nil)}})
+
+	e := w.NewEntity()
+	e.Replace(&Component1{})
+
+	require.Equal(t, 1, q.Len())
+	require.Equal(t, []Entity{e}, q.Entities())
+	require.Equal(t, 0, q.Len())
+	require.Len(t, q.Entities(), 0)
+
+	e.MarkChanged((*Component1)(nil))
+	require.Equal(t, []Entity{e}, q.Entities())
+}
+
+var _ SystemUpdater = (*ChangeSystem)(nil)
+
+type ChangeSystem struct {
+	Filter   SystemFilter
+	Filtered []Entity
+	OnUpdate func(filtered []Entity)
+}
+
+func (s *ChangeSystem) GetFilters() []SystemFilter {
+	return []SystemFilter{s.Filter}
+}
+
+func (s *ChangeSystem) Update(_ time.Duration, filtered [][]Entity) {
+	s.Filtered = append([]Entity(nil), filtered[0]...)
+
+	if s.OnUpdate != nil {
+		s.OnUpdate(filtered[0])
+	}
+}
+
+// OtherChangeSystem has a different type to be added to the world along with ChangeSystem.
+type OtherChangeSystem struct {
+	ChangeSystem
+}
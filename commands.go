@@ -0,0 +1,129 @@
+package gecs
+
+import (
+	"sync"
+)
+
+// Commands is a buffer of the deferred structural changes.
+// The world applies its buffer at the end of every stage and after the update, see World.Commands.
+// It is safe for concurrent use, but the order of the commands pushed concurrently is not deterministic,
+// so the systems updated in parallel should use their own buffers, see World.SystemCommands.
+type Commands struct {
+	mu       sync.Mutex
+	commands []command
+}
+
+type commandKind int
+
+const (
+	commandSpawn commandKind = iota
+	commandDestroy
+	commandAdd
+	commandRemove
+)
+
+type command struct {
+	kind       commandKind
+	e          Entity
+	components []Component
+}
+
+// Spawn creates a new entity with the components.
+func (c *Commands) Spawn(cs ...Component) {
+	c.push(command{kind: commandSpawn, components: cs})
+}
+
+// Destroy destroys the entity.
+func (c *Commands) Destroy(e Entity) {
+	c.push(command{kind: commandDestroy, e: e})
+}
+
+// Add adds the component to the entity or replaces the existing one, the same as Entity.Replace.
+func (c *Commands) Add(e Entity, comp Component) {
+	c.push(command{kind: commandAdd, e: e, components: []Component{comp}})
+}
+
+// Remove deletes the component with the passed type from the entity, the same as Entity.Delete.
+func (c *Commands) Remove(e Entity, comp Component) {
+	c.push(command{kind: commandRemove, e: e, components: []Component{comp}})
+}
+
+func (c *Commands) push(cmd command) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.commands = append(c.commands, cmd)
+}
+
+// take returns the buffered commands and clears the buffer.
+func (c *Commands) take() []command {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cmds := c.commands
+	c.commands = nil
+	return cmds
+}
+
+// apply applies the commands to the world in the order they were added and returns true if there were any.
+// The commands added while applying, e.g. by the observers, are applied too.
+func (c *Commands) apply(w *world) bool {
+	applied := false
+	for cmds := c.take(); len(cmds) > 0; cmds = c.take() {
+		applied = true
+
+		for _, cmd := range cmds {
+			switch cmd.kind {
+			case commandSpawn:
+				e := w.NewEntity()
+				for _, comp := range cmd.components {
+					e.Replace(comp)
+				}
+			case commandDestroy:
+				cmd.e.Destroy()
+			case commandAdd:
+				cmd.e.Replace(cmd.components[0])
+			case commandRemove:
+				cmd.e.Delete(cmd.components[0])
+			}
+		}
+	}
+
+	return applied
+}
+
+// applyCommands applies the world buffer and then the system buffers in the systems order,
+// until the commands added while applying are applied too.
+func (w *world) applyCommands() {
+	order, _ := w.systemsOrder()
+
+	for applied := true; applied; {
+		applied = w.commands.apply(w)
+		for _, e := range order {
+			if e.commands.apply(w) {
+				applied = true
+			}
+		}
+	}
+}
+
+func (w *world) Commands() *Commands {
+	return w.commands
+}
+
+func (w *world) Inbox() *Commands {
+	return w.inbox
+}
+
+func (w *world) SystemCommands(s System) *Commands {
+	w.lock()
+	defer w.unlock()
+
+	for _, e := range w.systems {
+		if systemMatches(s, e.system) {
+			return e.commands
+		}
+	}
+
+	return w.commands
+}
@@ -0,0 +1,116 @@
+package gecs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommands_Apply(t *testing.T) {
+	w := NewWorld().(*world)
+
+	e1 := w.NewEntity()
+	e1.Replace(&Component1{Num: 1})
+	e2 := w.NewEntity()
+	e2.Replace(&Component1{Num: 2})
+
+	cmd := w.Commands()
+	cmd.Spawn(&Component1{Num: 3}, &Component2{Text: "spawned"})
+	cmd.Add(e1, &Component2{Text: "added"})
+	cmd.Remove(e1, (*Component1)(nil))
+	cmd.Destroy(e2)
+
+	require.Equal(t, 2, w.EntityCount(), "Commands should be deferred")
+	require.True(t, e1.Has((*Component1)(nil)))
+
+	w.commands.apply(w)
+
+	require.Equal(t, 2, w.EntityCount())
+	require.False(t, e1.Has((*Component1)(nil)))
+	require.Equal(t, "added", Get[*Component2](e1).Text)
+	require.False(t, e2.Alive())
+
+	q := w.Query(SystemFilter{Include: []Component{(*Component1)(nil), (*Component2)(nil)}})
+	es := q.Entities()
+	require.Len(t, es, 1)
+	require.Equal(t, 3, Get[*Component1](es[0]).Num)
+}
+
+func TestCommands_StageSyncPoint(t *testing.T) {
+	w := NewWorld()
+
+	e := w.NewEntity()
+	e.Replace(&Component1{Num: 1})
+
+	var seen []bool
+	w.AddSystem(&AccessSystem{OnUpdate: func() {
+		w.Commands().Add(e, &Component2{})
+	}})
+	w.AddSystem(&AccessSystem2{AccessSystem{OnUpdate: func() {
+		seen = append(seen, e.Has((*Component2)(nil)))
+	}}})
+	w.AddSystem(&AccessSystem3{AccessSystem{OnUpdate: func() {
+		seen = append(seen, e.Has((*Component2)(nil)))
+	}}}, InStage(StagePostUpdate))
+
+	require.NoError(t, w.SystemsUpdate(time.Second))
+	require.Equal(t, []bool{false, true}, seen, "Commands should be applied at the end of the stage")
+}
+
+func TestCommands_Parallel(t *testing.T) {
+	w := NewWorld(WithParallelSystems())
+
+	spawn := func() { w.Commands().Spawn(&Component1{}) }
+	s1 := &AccessSystem{Acc: SystemAccess{Read: []Component{(*Component2)(nil)}}, OnUpdate: spawn}
+	s2 := &AccessSystem2{AccessSystem{Acc: SystemAccess{Read: []Component{(*Component2)(nil)}}, OnUpdate: spawn}}
+	w.AddSystem(s1)
+	w.AddSystem(s2)
+	w.NewEntity().Replace(&Component2{})
+
+	require.NoError(t, w.SystemsUpdate(time.Second))
+	require.Equal(t, 3, w.EntityCount())
+}
+
+func TestCommands_SystemCommandsOrder(t *testing.T) {
+	w := NewWorld(WithParallelSystems())
+
+	// The second system pushes its command first, but the first system's command is applied first.
+	pushed := make(chan struct{})
+	var s1, s2 *AccessSystem
+	s1 = &AccessSystem{Acc: SystemAccess{Write: []Component{(*Component1)(nil)}}, OnUpdate: func() {
+		<-pushed
+		w.SystemCommands(s1).Spawn(&Component3{Flag: true})
+	}}
+	s2 = &AccessSystem{Acc: SystemAccess{Write: []Component{(*Component2)(nil)}}, OnUpdate: func() {
+		w.SystemCommands(s2).Spawn(&Component3{Flag: false})
+		close(pushed)
+	}}
+	w.AddSystem(s1)
+	w.AddSystem(s2)
+	require.Equal(t, [][]System{{s1, s2}}, batchSystems(w.(*world)))
+	require.Equal(t, w.Commands(), w.SystemCommands(&AccessSystem{}), "not added system uses the world buffer")
+
+	require.NoError(t, w.SystemsUpdate(time.Second))
+
+	es := w.Entities()
+	require.Len(t, es, 2)
+	require.True(t, es[0].ID() < es[1].ID())
+	require.True(t, Get[*Component3](es[0]).Flag, "the first system commands should be applied first")
+	require.False(t, Get[*Component3](es[1]).Flag)
+
+	t.Run("Removed system commands are applied", func(t *testing.T) {
+		s2.OnUpdate = nil
+		w.SystemCommands(s1).Spawn(&Component3{})
+		w.RemoveSystem(s1)
+		require.NoError(t, w.SystemsUpdate(time.Second))
+		require.Equal(t, 3, w.EntityCount())
+	})
+
+	t.Run("Replaced system commands are applied", func(t *testing.T) {
+		w.SystemCommands(s2).Spawn(&Component3{})
+		w.AddSystem(s2)
+		require.NoError(t, w.SystemsUpdate(time.Second))
+		require.Equal(t, 4, w.EntityCount())
+	})
+}
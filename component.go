@@ -0,0 +1,40 @@
+package gecs
+
+// Get returns the component of type T from the entity.
+// If the entity doesn't have the component, the zero value of T is returned.
+func Get[T Component](e Entity) T {
+	c, _ := TryGet[T](e)
+	return c
+}
+
+// TryGet returns the component of type T from the entity and true,
+// or the zero value of T and false if the entity doesn't have the component.
+func TryGet[T Component](e Entity) (T, bool) {
+	var zero T
+
+	if !e.Has(zero) {
+		return zero, false
+	}
+
+	c, ok := e.Get(zero).(T)
+	return c, ok
+}
+
+// Has returns true if there is a component of type T on the entity.
+func Has[T Component](e Entity) bool {
+	var zero T
+	return e.Has(zero)
+}
+
+// Add adds the component to the entity, or replaces it if a component of type T already exists.
+// Returns the passed component.
+func Add[T Component](e Entity, c T) T {
+	e.Replace(c)
+	return c
+}
+
+// Remove removes the component of type T from the entity.
+func Remove[T Component](e Entity) {
+	var zero T
+	e.Delete(zero)
+}
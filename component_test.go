@@ -0,0 +1,108 @@
+package gecs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGet(t *testing.T) {
+	w := NewWorld()
+	e := w.NewEntity()
+	e.Replace(&Component1{Num: 42})
+
+	t.Run("Exist component", func(t *testing.T) {
+		c := Get[*Component1](e)
+		require.NotNil(t, c)
+		require.Equal(t, 42, c.Num)
+	})
+
+	t.Run("Not exist component", func(t *testing.T) {
+		require.Nil(t, Get[*Component2](e))
+		require.False(t, e.Has((*Component2)(nil)), "Get should not add the component")
+	})
+}
+
+func TestTryGet(t *testing.T) {
+	w := NewWorld()
+	e := w.NewEntity()
+	e.Replace(&Component1{Num: 42})
+
+	c1, ok := TryGet[*Component1](e)
+	require.True(t, ok)
+	require.Equal(t, 42, c1.Num)
+
+	c2, ok := TryGet[*Component2](e)
+	require.False(t, ok)
+	require.Nil(t, c2)
+}
+
+func TestHas(t *testing.T) {
+	w := NewWorld()
+	e := w.NewEntity()
+	e.Replace(&Component1{Num: 42})
+
+	require.True(t, Has[*Component1](e))
+	require.False(t, Has[*Component2](e))
+}
+
+func TestAdd(t *testing.T) {
+	w := NewWorld()
+	e := w.NewEntity()
+
+	t.Run("Add component", func(t *testing.T) {
+		c := Add(e, &Component1{Num: 42})
+		require.Equal(t, 42, c.Num)
+		require.Equal(t, c, Get[*Component1](e))
+	})
+
+	t.Run("Replace component", func(t *testing.T) {
+		c := Add(e, &Component1{Num: 1234})
+		require.Equal(t, 1234, Get[*Component1](e).Num)
+		require.Equal(t, c, Get[*Component1](e))
+	})
+
+	t.Run("Add nil component", func(t *testing.T) {
+		Add[*Component2](e, nil)
+		require.False(t, Has[*Component2](e))
+	})
+}
+
+func TestRemove(t *testing.T) {
+	w := NewWorld()
+	e := w.NewEntity()
+	e.Replace(&Component1{Num: 42})
+	e.Replace(&Component2{Text: "Hello world"})
+
+	Remove[*Component1](e)
+	require.False(t, Has[*Component1](e))
+	require.True(t, Has[*Component2](e))
+
+	// Remove removed component
+	Remove[*Component1](e)
+	require.False(t, Has[*Component1](e))
+	require.True(t, Has[*Component2](e))
+}
+
+func TestValueComponent(t *testing.T) {
+	w := NewWorld()
+	e := w.NewEntity()
+
+	require.NotPanics(t, func() {
+		Add(e, Component1{Num: 1})
+		Add(e, Component1{Num: 2})
+	})
+	require.True(t, Has[Component1](e))
+	require.False(t, Has[*Component1](e), "the value and the pointer are different component types")
+	require.Equal(t, Component1{Num: 2}, Get[Component1](e))
+
+	c, ok := TryGet[Component1](e)
+	require.True(t, ok)
+	require.Equal(t, 2, c.Num)
+
+	Remove[Component1](e)
+	require.False(t, Has[Component1](e))
+
+	_, ok = TryGet[Component1](e)
+	require.False(t, ok)
+}
@@ -0,0 +1,80 @@
+package gecs
+
+// RunIf makes the system update only when the predicate returns true.
+func RunIf(fn func(w World) bool) SystemOption {
+	return func(e *systemEntry) {
+		e.conditions = append(e.conditions, fn)
+	}
+}
+
+// EveryTicks makes the system update only every n-th tick, starting from the first one.
+// The skipped deltas are not accumulated, the system receives the delta of its update tick only.
+func EveryTicks(n uint) SystemOption {
+	return func(e *systemEntry) {
+		e.every = uint64(n)
+	}
+}
+
+// RunIfNotEmpty makes the system update only when some entity matches the filter.
+// The change detection conditions of the filter are ignored.
+func RunIfNotEmpty(f SystemFilter) SystemOption {
+	f.Added = nil
+	f.Changed = nil
+	f.Removed = nil
+
+	return func(e *systemEntry) {
+		e.nonEmpty = append(e.nonEmpty, f)
+	}
+}
+
+func (w *world) SetSystemEnabled(s System, enabled bool) {
+	w.lock()
+	defer w.unlock()
+
+	for _, e := range w.systems {
+		if !systemMatches(s, e.system) || e.disabled == !enabled {
+			continue
+		}
+
+		e.disabled = !enabled
+		if enabled {
+			e.tick = w.tick
+		}
+	}
+}
+
+// systemShouldRun returns true if the system is enabled and its run conditions are met.
+func (w *world) systemShouldRun(e *systemEntry) bool {
+	if e.disabled {
+		return false
+	}
+
+	if e.every > 1 {
+		skip := e.skipped != 0
+		e.skipped = (e.skipped + 1) % e.every
+		if skip {
+			return false
+		}
+	}
+
+	for _, q := range e.queries {
+		if q.Len() == 0 {
+			return false
+		}
+	}
+
+	for _, fn := range e.conditions {
+		if !fn(w) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// close releases the resources of the removed system.
+func (e *systemEntry) close() {
+	for _, q := range e.queries {
+		q.close()
+	}
+}
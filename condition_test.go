@@ -0,0 +1,84 @@
+package gecs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCondition_SetSystemEnabled(t *testing.T) {
+	w := NewWorld().(*world)
+
+	s := &AccessSystem{Acc: SystemAccess{Read: []Component{(*Component1)(nil)}}}
+	w.AddSystem(s)
+	f := w.systems[0].filters[0]
+
+	w.SetSystemEnabled(s, false)
+
+	e := w.NewEntity()
+	e.Replace(&Component1{})
+	w.SystemsUpdate(time.Second)
+	require.Equal(t, 0, s.Updates)
+	require.Len(t, f.archetypes, 1, "Filter of the disabled system should be kept up to date")
+
+	w.SetSystemEnabled((*AccessSystem)(nil), true)
+	w.SystemsUpdate(time.Second)
+	require.Equal(t, 1, s.Updates)
+	require.Len(t, s.Filtered[0], 1)
+}
+
+func TestCondition_EveryTicks(t *testing.T) {
+	w := NewWorld()
+
+	s := &AccessSystem{}
+	w.AddSystem(s, EveryTicks(3))
+
+	var updates []int
+	for i := 0; i < 7; i++ {
+		w.SystemsUpdate(time.Second)
+		updates = append(updates, s.Updates)
+	}
+
+	require.Equal(t, []int{1, 1, 1, 2, 2, 2, 3}, updates)
+}
+
+func TestCondition_RunIf(t *testing.T) {
+	w := NewWorld()
+
+	run := false
+	s := &AccessSystem{}
+	w.AddSystem(s, RunIf(func(World) bool { return run }))
+
+	w.SystemsUpdate(time.Second)
+	require.Equal(t, 0, s.Updates)
+
+	run = true
+	w.SystemsUpdate(time.Second)
+	require.Equal(t, 1, s.Updates)
+}
+
+func TestCondition_RunIfNotEmpty(t *testing.T) {
+	w := NewWorld().(*world)
+
+	s := &AccessSystem{}
+	w.AddSystem(s, RunIfNotEmpty(SystemFilter{Include: []Component{(*Component2)(nil)}}))
+
+	w.SystemsUpdate(time.Second)
+	require.Equal(t, 0, s.Updates)
+
+	e := w.NewEntity()
+	e.Replace(&Component2{})
+	w.SystemsUpdate(time.Second)
+	require.Equal(t, 1, s.Updates)
+
+	e.Delete((*Component2)(nil))
+	w.SystemsUpdate(time.Second)
+	require.Equal(t, 1, s.Updates)
+
+	t.Run("Query is closed with the system", func(t *testing.T) {
+		require.Len(t, w.queries, 1)
+		w.RemoveSystem(s)
+		require.Len(t, w.queries, 0)
+	})
+}
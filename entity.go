@@ -8,11 +8,19 @@ import (
 type Component interface{}
 
 // Entity ecs interface.
+//
+// An entity handle becomes stale after Destroy. All operations on a stale handle are ignored,
+// the destroyed entity is never restored, even if its ID slot is reused by a new entity.
 type Entity interface {
+	// ID returns the entity identifier, which contains the slot index in the lower 32 bits
+	// and the slot generation in the upper 32 bits.
 	ID() uint64
 
+	// Alive returns true if the entity has not been destroyed.
+	Alive() bool
+
 	// Destroy removes all components and removes the entity from the world.
-	// In case someone holds a reference to the entity and adds a new component, the entity will be restored.
+	// The OnRemove observers are called for every component before the removal.
 	Destroy()
 
 	// Get gets an existing component with the type of the passed component.
@@ -27,124 +35,266 @@ type Entity interface {
 	Replace(c Component)
 
 	// Delete removes the component with the passed type.
+	// The entity without components stays alive, unless the world is created WithAutoDestroy.
 	Delete(c Component)
 
+	// MarkChanged marks the component with the passed type as changed for the Changed filters.
+	// Use it after mutating the component in place through the pointer, Replace marks the component itself.
+	MarkChanged(c Component)
+
 	// Components returns all entity component.
 	Components() []Component
 }
 
 type entity struct {
-	w              *world
-	id             uint64
-	componentCount uint64
-	destroyed      bool
+	w         *world
+	id        uint64
+	archetype *archetype
+	row       int
+
+	// removeNotified contains the components, which the OnRemove observers have been notified about during Destroy.
+	removeNotified map[componentID]bool
 }
 
 func (e *entity) ID() uint64 {
 	return e.id
 }
 
+func (e *entity) Alive() bool {
+	e.w.lock()
+	defer e.w.unlock()
+
+	return e.w.entityAlive(e)
+}
+
 func (e *entity) Destroy() {
-	e.destroyed = true
+	e.w.lock()
+	defer e.w.unlock()
 
-	for i, ee := range e.w.entities {
-		if ee.ID() == e.ID() {
-			e.w.entities = append(e.w.entities[:i], e.w.entities[i+1:]...)
-			break
-		}
-	}
+	e.w.entityAccessed(e)
+	e.destroy()
+}
 
-	for ct, m := range e.w.components {
-		delete(m, e)
+// destroy removes the entity from the world, notifying the observers.
+func (e *entity) destroy() {
+	if !e.w.entityAlive(e) {
+		return
+	}
 
-		if len(e.w.components[ct]) == 0 {
-			delete(e.w.components, ct)
+	if len(e.w.observers) > 0 {
+		// Observers may change the entity, so every component is looked up again before its notification.
+		// The components deleted by the observers have been notified by Delete, the added ones are notified too.
+		notified := make(map[componentID]bool, len(e.archetype.ids))
+		e.removeNotified = notified
+		defer func() { e.removeNotified = nil }()
+
+		for {
+			if !e.w.entityAlive(e) {
+				return
+			}
+
+			col := -1
+			for i, id := range e.archetype.ids {
+				if !notified[id] {
+					col = i
+					break
+				}
+			}
+			if col < 0 {
+				break
+			}
+
+			id := e.archetype.ids[col]
+			notified[id] = true
+			e.w.notifyRemove(e, id, e.archetype.columns[col][e.row])
 		}
 	}
 
-	e.w.systemCacheDeleteEntityFromAllSystems(e)
+	e.w.destroyEntity(e)
 }
 
 func (e *entity) Get(c Component) Component {
+	e.w.lock()
+	defer e.w.unlock()
+
 	return e.getOrReplace(c, false)
 }
 
 func (e *entity) Has(c Component) bool {
-	cs := e.w.components[reflect.TypeOf(c)]
-	if cs == nil {
+	e.w.lock()
+	defer e.w.unlock()
+
+	e.w.entityAccessed(e)
+	if e.archetype == nil {
 		return false
 	}
 
-	_, ok := cs[e]
-	return ok
+	k := e.w.termKey(c)
+	if k.wildcard() {
+		for _, id := range e.archetype.ids {
+			if k.matches(e.w.componentKey(id)) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	id, ok := e.w.componentIDs[k]
+	return ok && e.archetype.has(id)
 }
 
 func (e *entity) Replace(c Component) {
+	e.w.lock()
+	defer e.w.unlock()
+
 	e.getOrReplace(c, true)
 }
 
 func (e *entity) Delete(c Component) {
-	ct := reflect.TypeOf(c)
+	e.w.lock()
+	defer e.w.unlock()
 
-	_, ok := e.w.components[ct][e]
+	e.w.entityAccessed(e)
+	id, ok := e.w.componentIDs[e.w.termKey(c)]
 	if !ok {
 		return
 	}
 
-	delete(e.w.components[ct], e)
-	if len(e.w.components[ct]) == 0 {
-		delete(e.w.components, ct)
+	e.deleteID(id)
+}
+
+// deleteID removes the component with the id, notifying the observers.
+func (e *entity) deleteID(id componentID) {
+	if e.archetype == nil || !e.archetype.has(id) {
+		return
+	}
+
+	if !e.removeNotified[id] {
+		e.w.notifyRemove(e, id, e.archetype.columns[e.archetype.column(id)][e.row])
 	}
 
-	e.componentCount--
-	if e.componentCount == 0 {
-		e.Destroy()
+	// The observer may have changed the entity.
+	if e.archetype == nil || !e.archetype.has(id) {
+		return
+	}
+
+	e.w.recordRemoved(e, id)
+
+	if e.w.autoDestroy && len(e.archetype.ids) == 1 {
+		// The observers have already been notified about the last component.
+		e.w.destroyEntity(e)
 		return
 	}
 
-	e.w.systemCacheRebuildByEntity(e)
+	e.w.moveEntity(e, e.w.archetypeWithout(e.archetype, id))
+}
+
+func (e *entity) MarkChanged(c Component) {
+	e.w.lock()
+	defer e.w.unlock()
+
+	e.w.entityAccessed(e)
+	id, ok := e.w.componentIDs[e.w.termKey(c)]
+	if !ok || e.archetype == nil {
+		return
+	}
+
+	col := e.archetype.column(id)
+	if col < 0 {
+		return
+	}
+
+	e.archetype.changed[col][e.row] = e.w.tick
 }
 
 func (e *entity) Components() []Component {
+	e.w.lock()
+	defer e.w.unlock()
+
+	e.w.entityAccessed(e)
+	return e.components()
+}
+
+func (e *entity) components() []Component {
+	if e.archetype == nil {
+		return nil
+	}
+
 	var cs []Component
 
-	for _, ec := range e.w.components {
-		cs = append(cs, ec[e])
+	for _, col := range e.archetype.columns {
+		cs = append(cs, col[e.row])
 	}
 
 	return cs
 }
 
 func (e *entity) getOrReplace(c Component, replace bool) Component {
-	if c == nil {
+	e.w.entityAccessed(e)
+	// A destroyed entity has no archetype, so the stale handle can neither read nor add components.
+	if c == nil || e.archetype == nil {
+		return nil
+	}
+
+	// The pairs are added by SetPair, the term itself is not a component.
+	if _, ok := c.(pairTerm); ok {
 		return nil
 	}
 
+	// The component type is registered only on addition, so that reading doesn't change the world.
 	ct := reflect.TypeOf(c)
-	cs := e.w.components[ct]
-	if cs == nil {
-		cs = make(map[Entity]Component)
-		e.w.components[ct] = cs
+	id, ok := e.w.componentIDs[componentKey{t: ct}]
+
+	col := -1
+	if ok {
+		col = e.archetype.column(id)
 	}
+	if col >= 0 {
+		if !replace {
+			return e.archetype.columns[col][e.row]
+		}
 
-	if !replace {
-		v, ok := cs[e]
-		if ok {
-			return v
+		if isNil(c) {
+			return nil
 		}
+
+		return e.set(id, c)
 	}
 
-	if reflect.ValueOf(c).IsNil() {
+	if isNil(c) {
 		return nil
 	}
 
-	if e.destroyed {
-		e.w.entities = append(e.w.entities, e)
-		e.destroyed = false
+	return e.set(e.w.componentTypeID(ct), c)
+}
+
+// isNil returns true if the component is a nil value of the kind, which can be nil, e.g. a nil pointer.
+// The value type components, e.g. structs, are never nil.
+func isNil(c Component) bool {
+	v := reflect.ValueOf(c)
+
+	// nolint: exhaustive
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface, reflect.Func, reflect.Chan:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
+
+// set adds the component with the id to the entity, or replaces the existing one, notifying the observers.
+func (e *entity) set(id componentID, c Component) Component {
+	if col := e.archetype.column(id); col >= 0 {
+		old := e.archetype.columns[col][e.row]
+		e.archetype.set(col, e.row, c, e.w.tick, false)
+		e.w.notifyReplace(e, id, old, c)
+		return c
 	}
 
-	e.componentCount++
-	e.w.components[reflect.TypeOf(c)][e] = c
-	e.w.systemCacheRebuildByEntity(e)
+	a := e.w.archetypeWith(e.archetype, id)
+	e.w.moveEntity(e, a)
+	a.set(a.column(id), e.row, c, e.w.tick, true)
+	e.w.notifyAdd(e, id, c)
 	return c
 }
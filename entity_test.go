@@ -121,34 +121,99 @@ func TestEntity_Delete(t *testing.T) {
 	e.Replace(&Component1{Num: 42})
 	e.Replace(&Component2{Text: "Hello world"})
 
-	require.False(t, e.(*entity).destroyed)
-	require.EqualValues(t, e.(*entity).componentCount, 2)
+	require.True(t, e.Alive())
+	require.Len(t, e.Components(), 2)
 
 	e.Delete((*Component1)(nil))
-	require.False(t, e.(*entity).destroyed)
-	require.EqualValues(t, e.(*entity).componentCount, 1)
+	require.True(t, e.Alive())
+	require.Len(t, e.Components(), 1)
 	require.Nil(t, e.Get((*Component1)(nil)))
 	require.NotNil(t, e.Get((*Component2)(nil)))
 
 	// Remove removed component
 	e.Delete((*Component1)(nil))
-	require.False(t, e.(*entity).destroyed)
-	require.EqualValues(t, e.(*entity).componentCount, 1)
+	require.True(t, e.Alive())
+	require.Len(t, e.Components(), 1)
 	require.Nil(t, e.Get((*Component1)(nil)))
 	require.NotNil(t, e.Get((*Component2)(nil)))
 
+	// Entity without components stays alive
 	e.Delete((*Component2)(nil))
-	require.True(t, e.(*entity).destroyed)
-	require.EqualValues(t, e.(*entity).componentCount, 0)
+	require.True(t, e.Alive())
+	require.Len(t, e.Components(), 0)
 	require.Nil(t, e.Get((*Component1)(nil)))
 	require.Nil(t, e.Get((*Component2)(nil)))
 
 	e.Replace(&Component1{Num: 123})
-	require.False(t, e.(*entity).destroyed)
-	require.EqualValues(t, e.(*entity).componentCount, 1)
+	require.True(t, e.Alive())
+	require.Len(t, e.Components(), 1)
 	require.NotNil(t, e.Get((*Component1)(nil)))
 }
 
+func TestEntity_DeleteWithAutoDestroy(t *testing.T) {
+	w := NewWorld(WithAutoDestroy())
+	e := w.NewEntity()
+	e.Replace(&Component1{Num: 42})
+	e.Replace(&Component2{Text: "Hello world"})
+
+	e.Delete((*Component1)(nil))
+	require.True(t, e.Alive())
+	require.Len(t, e.Components(), 1)
+
+	e.Delete((*Component2)(nil))
+	require.False(t, e.Alive())
+	require.Len(t, e.Components(), 0)
+	require.Equal(t, 0, w.EntityCount())
+
+	// Destroyed entity is not restored
+	e.Replace(&Component1{Num: 123})
+	require.False(t, e.Alive())
+	require.Len(t, e.Components(), 0)
+	require.Nil(t, e.Get((*Component1)(nil)))
+}
+
+func TestEntity_Destroy(t *testing.T) {
+	w := NewWorld()
+	e := w.NewEntity()
+	e.Replace(&Component1{Num: 42})
+	id := e.ID()
+
+	e.Destroy()
+	require.False(t, e.Alive())
+	require.False(t, e.Has((*Component1)(nil)))
+
+	t.Run("Destroy stale entity", func(t *testing.T) {
+		e.Destroy()
+		require.False(t, e.Alive())
+	})
+
+	t.Run("Stale entity operations are ignored", func(t *testing.T) {
+		require.Nil(t, e.Get(&Component1{Num: 1}))
+		e.Replace(&Component2{Text: "Hello world"})
+		e.Delete((*Component1)(nil))
+
+		require.False(t, e.Alive())
+		require.Nil(t, e.Components())
+	})
+
+	t.Run("Slot reuse", func(t *testing.T) {
+		e2 := w.NewEntity()
+		e2.Replace(&Component2{Text: "Hello world"})
+
+		require.Equal(t, entityIndex(id), entityIndex(e2.ID()), "Slot should be reused")
+		require.NotEqual(t, id, e2.ID(), "Generation should be increased")
+		require.True(t, e2.Alive())
+		require.False(t, e.Alive())
+
+		// Stale handle doesn't affect the new entity in the same slot
+		e.Destroy()
+		e.Replace(&Component1{Num: 1})
+		require.True(t, e2.Alive())
+		require.False(t, e2.Has((*Component1)(nil)))
+		require.True(t, e2.Has((*Component2)(nil)))
+	})
+}
+
 func TestEntity_Components(t *testing.T) {
 	w := NewWorld()
 	e := w.NewEntity()
@@ -0,0 +1,43 @@
+package gecs
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+)
+
+// ErrorPolicy defines how the world handles the system update errors.
+type ErrorPolicy int
+
+const (
+	// ErrorPolicyStop stops the update, SystemsUpdate and Run return the error.
+	ErrorPolicyStop ErrorPolicy = iota
+	// ErrorPolicyLog logs the error and continues the update.
+	ErrorPolicyLog
+	// ErrorPolicyDisable logs the error and disables the failed system, it can be enabled by World.SetSystemEnabled.
+	ErrorPolicyDisable
+)
+
+// systemError handles the system update error according to the world error policy.
+func (w *world) systemError(e *systemEntry, err error) {
+	var p *SystemPanic
+	if errors.As(err, &p) {
+		w.systemPanic(e, p)
+		return
+	}
+
+	err = fmt.Errorf("%s: %w", reflect.TypeOf(e.system).String(), err)
+
+	switch w.errorPolicy {
+	case ErrorPolicyLog:
+		log.Printf("gecs: system update: %v", err)
+	case ErrorPolicyDisable:
+		log.Printf("gecs: system update: %v, the system is disabled", err)
+		e.disabled = true
+	default:
+		if w.updateErr == nil {
+			w.updateErr = err
+		}
+	}
+}
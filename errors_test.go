@@ -0,0 +1,103 @@
+package gecs
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errUpdate = errors.New("update failed")
+
+func TestErrorPolicy_Stop(t *testing.T) {
+	w := NewWorld()
+
+	failing := &FailingSystem{Err: errUpdate}
+	next := &AccessSystem{}
+	w.AddSystem(failing)
+	w.AddSystem(next)
+
+	err := w.SystemsUpdate(time.Second)
+	require.ErrorIs(t, err, errUpdate)
+	require.Contains(t, err.Error(), "*gecs.FailingSystem")
+	require.Equal(t, 0, next.Updates, "Remaining systems should not be updated")
+
+	t.Run("Run returns the error", func(t *testing.T) {
+		require.ErrorIs(t, w.Run(1000), errUpdate)
+	})
+
+	t.Run("Next update after the error", func(t *testing.T) {
+		failing.Err = nil
+		require.NoError(t, w.SystemsUpdate(time.Second))
+		require.Equal(t, 1, next.Updates)
+	})
+}
+
+func TestErrorPolicy_Log(t *testing.T) {
+	w := NewWorld(WithErrorPolicy(ErrorPolicyLog))
+
+	failing := &FailingSystem{Err: errUpdate}
+	next := &AccessSystem{}
+	w.AddSystem(failing)
+	w.AddSystem(next)
+
+	require.NoError(t, w.SystemsUpdate(time.Second))
+	require.NoError(t, w.SystemsUpdate(time.Second))
+	require.Equal(t, 2, failing.Updates)
+	require.Equal(t, 2, next.Updates)
+}
+
+func TestErrorPolicy_Disable(t *testing.T) {
+	w := NewWorld(WithErrorPolicy(ErrorPolicyDisable))
+
+	failing := &FailingSystem{Err: errUpdate}
+	next := &AccessSystem{}
+	w.AddSystem(failing)
+	w.AddSystem(next)
+
+	require.NoError(t, w.SystemsUpdate(time.Second))
+	require.NoError(t, w.SystemsUpdate(time.Second))
+	require.Equal(t, 1, failing.Updates)
+	require.Equal(t, 2, next.Updates)
+
+	w.SetSystemEnabled(failing, true)
+	require.NoError(t, w.SystemsUpdate(time.Second))
+	require.Equal(t, 2, failing.Updates)
+}
+
+func TestErrorPolicy_Parallel(t *testing.T) {
+	w := NewWorld(WithParallelSystems())
+
+	failing := &FailingSystem{Err: errUpdate}
+	other := &AccessSystem{Acc: SystemAccess{Write: []Component{(*Component2)(nil)}}}
+	exclusive := &AccessSystem2{AccessSystem{Acc: SystemAccess{Exclusive: true}}}
+	w.AddSystem(failing)
+	w.AddSystem(other)
+	w.AddSystem(exclusive)
+
+	require.ErrorIs(t, w.SystemsUpdate(time.Second), errUpdate)
+	require.Equal(t, 1, other.Updates, "The systems of the same batch are updated")
+	require.Equal(t, 0, exclusive.Updates)
+}
+
+var _ SystemTryUpdater = (*FailingSystem)(nil)
+
+type FailingSystem struct {
+	Err error
+
+	Updates int
+}
+
+func (s *FailingSystem) GetFilters() []SystemFilter {
+	return nil
+}
+
+func (s *FailingSystem) Access() SystemAccess {
+	return SystemAccess{}
+}
+
+func (s *FailingSystem) TryUpdate(time.Duration, [][]Entity) error {
+	s.Updates++
+	return s.Err
+}
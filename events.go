@@ -0,0 +1,168 @@
+package gecs
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Events is a queue of the events of type T, a lighter alternative to the event entities.
+// The events are kept for two world updates, after the update in which they were sent and the next one,
+// so every reader updated on each tick sees every event exactly once, regardless of the systems order.
+// In the fixed timestep mode the events are also kept for two fixed steps, so the readers of the fixed rate systems
+// don't miss them either. The readers of the systems updated less often, e.g. disabled or with EveryTicks,
+// see only the events sent since the previous update.
+// It is safe for concurrent use.
+type Events[T any] struct {
+	mu sync.Mutex
+
+	events        []T
+	start         uint64 // the sequence number of the first kept event
+	prevStart     uint64 // the sequence number of the first event sent during the previous update
+	curStart      uint64 // the sequence number of the first event sent during the current update
+	stepPrevStart uint64 // the same as prevStart for the fixed steps
+	stepCurStart  uint64 // the same as curStart for the fixed steps
+}
+
+// EventsOf returns the events of type T of the world, creating them if necessary.
+// To send the events from other goroutines without WithLocking, get the events in advance and use Events.Send.
+func EventsOf[T any](w World) *Events[T] {
+	ww := w.(*world)
+
+	ww.lock()
+	defer ww.unlock()
+
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if es, ok := ww.events[t]; ok {
+		return es.(*Events[T])
+	}
+
+	es := &Events[T]{}
+	ww.events[t] = es
+	return es
+}
+
+// Send sends the event of type T to the world events.
+func Send[T any](w World, ev T) {
+	EventsOf[T](w).Send(ev)
+}
+
+// Send sends the event.
+func (es *Events[T]) Send(ev T) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	es.events = append(es.events, ev)
+}
+
+// update drops the events sent before the previous update and, if the fixed steps are running, before the previous step.
+// Otherwise, the steps follow the updates, so the events of the paused world are not kept.
+func (es *Events[T]) update(fixed bool) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	es.prevStart = es.curStart
+	es.curStart = es.start + uint64(len(es.events))
+	if !fixed {
+		es.stepPrevStart = es.prevStart
+		es.stepCurStart = es.curStart
+	}
+
+	drop := es.prevStart
+	if es.stepPrevStart < drop {
+		drop = es.stepPrevStart
+	}
+
+	if drop > es.start {
+		// The kept events are copied, so that the dropped ones are not referenced by the backing array.
+		es.events = append(es.events[:0], es.events[drop-es.start:]...)
+		es.start = drop
+	}
+}
+
+// step marks the end of the fixed step.
+func (es *Events[T]) step() {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	es.stepPrevStart = es.stepCurStart
+	es.stepCurStart = es.start + uint64(len(es.events))
+}
+
+// eventsUpdater is implemented by Events of any type.
+type eventsUpdater interface {
+	update(fixed bool)
+	step()
+}
+
+// updateEvents drops the events, which have been kept for two updates, and for two fixed steps if they are running.
+func (w *world) updateEvents(fixed bool) {
+	w.lock()
+	defer w.unlock()
+
+	for _, es := range w.events {
+		es.update(fixed)
+	}
+}
+
+// stepEvents marks the end of the fixed step for the events.
+func (w *world) stepEvents() {
+	w.lock()
+	defer w.unlock()
+
+	for _, es := range w.events {
+		es.step()
+	}
+}
+
+// EventReader reads the events of type T, tracking its own cursor, so each reader sees every event once.
+// The reader should be used by one system.
+type EventReader[T any] struct {
+	events *Events[T]
+	cursor uint64 // the sequence number of the next unread event
+	closed bool
+}
+
+// NewEventReader returns the reader of the world events of type T, which starts from the events
+// of the previous and the current update.
+func NewEventReader[T any](w World) *EventReader[T] {
+	es := EventsOf[T](w)
+
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	return &EventReader[T]{events: es, cursor: es.prevStart}
+}
+
+// Read returns the events sent since the previous call.
+func (r *EventReader[T]) Read() []T {
+	es := r.events
+
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	if r.closed {
+		return nil
+	}
+
+	if r.cursor < es.start {
+		r.cursor = es.start
+	}
+
+	var evs []T
+	if n := r.cursor - es.start; n < uint64(len(es.events)) {
+		evs = append(evs, es.events[n:]...)
+	}
+
+	r.cursor = es.start + uint64(len(es.events))
+	return evs
+}
+
+// Close makes the reader read nothing.
+func (r *EventReader[T]) Close() {
+	es := r.events
+
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	r.closed = true
+}
@@ -0,0 +1,106 @@
+package gecs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type TestEvent struct {
+	Num int
+}
+
+func TestEvents_ReadOnce(t *testing.T) {
+	w := NewWorld()
+	r := NewEventReader[TestEvent](w)
+
+	Send(w, TestEvent{Num: 1})
+	Send(w, TestEvent{Num: 2})
+
+	require.Equal(t, []TestEvent{{Num: 1}, {Num: 2}}, r.Read())
+	require.Empty(t, r.Read(), "Events should be read once")
+
+	Send(w, TestEvent{Num: 3})
+	require.Equal(t, []TestEvent{{Num: 3}}, r.Read())
+
+	t.Run("Readers have own cursors", func(t *testing.T) {
+		r2 := NewEventReader[TestEvent](w)
+		require.Equal(t, []TestEvent{{Num: 1}, {Num: 2}, {Num: 3}}, r2.Read())
+	})
+}
+
+func TestEvents_DoubleBuffer(t *testing.T) {
+	w := NewWorld()
+	r := NewEventReader[TestEvent](w)
+
+	Send(w, TestEvent{Num: 1})
+	require.NoError(t, w.SystemsUpdate(time.Second))
+
+	Send(w, TestEvent{Num: 2})
+	require.Equal(t, []TestEvent{{Num: 1}, {Num: 2}}, r.Read(), "Events should be kept for the next update")
+
+	require.NoError(t, w.SystemsUpdate(time.Second))
+	Send(w, TestEvent{Num: 3})
+	require.NoError(t, w.SystemsUpdate(time.Second))
+	require.NoError(t, w.SystemsUpdate(time.Second))
+
+	require.Empty(t, r.Read(), "Events should be dropped after two updates")
+	require.Empty(t, EventsOf[TestEvent](w).events)
+
+	t.Run("Closed reader", func(t *testing.T) {
+		r.Close()
+		Send(w, TestEvent{Num: 4})
+		require.Empty(t, r.Read())
+	})
+}
+
+func TestEvents_FixedTimestep(t *testing.T) {
+	w := NewWorld(WithFixedTimestep(100*time.Millisecond, 5))
+
+	fixed := NewEventReader[TestEvent](w)
+	variable := NewEventReader[TestEvent](w)
+	var fixedRead, variableRead []TestEvent
+
+	w.AddSystem(&AccessSystem{OnUpdate: func() { fixedRead = append(fixedRead, fixed.Read()...) }})
+	w.AddSystem(&AccessSystem2{AccessSystem{OnUpdate: func() {
+		variableRead = append(variableRead, variable.Read()...)
+	}}}, VariableRate())
+
+	var sent []TestEvent
+	for i := 0; i < 20; i++ {
+		ev := TestEvent{Num: i}
+		sent = append(sent, ev)
+		Send(w, ev)
+		require.NoError(t, w.SystemsUpdate(10*time.Millisecond))
+	}
+
+	require.Equal(t, sent, variableRead)
+	require.Equal(t, sent, fixedRead, "Fixed rate reader should see every event")
+
+	t.Run("Paused", func(t *testing.T) {
+		w.Pause()
+		Send(w, TestEvent{})
+		for i := 0; i < 3; i++ {
+			require.NoError(t, w.SystemsUpdate(10*time.Millisecond))
+		}
+		require.Empty(t, EventsOf[TestEvent](w).events, "Events should not be kept for the fixed steps of the paused world")
+	})
+}
+
+func TestEvents_SystemsOrder(t *testing.T) {
+	w := NewWorld()
+
+	r := NewEventReader[TestEvent](w)
+	var read [][]TestEvent
+
+	// The reader is updated before the writer, so it sees the events on the next update.
+	w.AddSystem(&AccessSystem{OnUpdate: func() { read = append(read, r.Read()) }})
+	w.AddSystem(&AccessSystem2{AccessSystem{OnUpdate: func() { Send(w, TestEvent{Num: len(read)}) }}})
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, w.SystemsUpdate(time.Second))
+	}
+
+	require.Equal(t, [][]TestEvent{nil, {{Num: 1}}, {{Num: 2}}}, read)
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"time"
 
 	term "github.com/nsf/termbox-go"
 
@@ -16,11 +17,9 @@ func main() {
 
 	defer term.Close()
 
-	w := ecs.NewWorld()
-	w.AddSystem(NewInputConsoleSystem(w))
-	w.AddSystem(&MovePlayerSystem{})
-	w.AddSystem(&RenderConsoleSystem{})
-	w.AddSystem(ecs.NewOneFrame((*InputEvent)(nil)))
+	w := ecs.NewWorld(ecs.WithAutoDestroy())
+	w.AddSystem(&MovePlayerSystem{input: ecs.NewEventReader[InputEvent](w)})
+	w.AddSystem(&RenderConsoleSystem{input: ecs.NewEventReader[InputEvent](w)})
 
 	for i := 0; i < 5; i++ {
 		playerEntity := w.NewEntity()
@@ -29,63 +28,40 @@ func main() {
 		playerEntity.Get(&RenderConsole{Char: 'w'})
 	}
 
-	w.Run(30)
-}
-
-func NewInputConsoleSystem(w ecs.World) *InputConsoleSystem {
-	s := &InputConsoleSystem{
-		w:           w,
-		lastPressed: make(chan term.Key),
-	}
-	go s.readConsoleInput()
-	return s
-}
+	go readConsoleInput(w, ecs.EventsOf[InputEvent](w))
 
-type InputConsoleSystem struct {
-	w           ecs.World
-	lastPressed chan term.Key
-}
-
-func (s *InputConsoleSystem) GetFilters() []ecs.SystemFilter {
-	return nil
-}
-
-func (s *InputConsoleSystem) Update(_ float32, _ [][]ecs.Entity) {
-	event := InputEvent{}
-
-	select {
-	case key := <-s.lastPressed:
-		// nolint: exhaustive
-		switch key {
-		case term.KeyCtrlC, term.KeyEsc:
-			s.w.Stop()
-
-		case term.KeyArrowUp:
-			event.Vertical = 1
-		case term.KeyArrowDown:
-			event.Vertical = -1
-		case term.KeyArrowLeft:
-			event.Horizontal = -1
-		case term.KeyArrowRight:
-			event.Horizontal = 1
-		}
-
-		if event.Vertical == 0 && event.Horizontal == 0 {
-			return
-		}
-
-		fmt.Println(event)
-		s.w.NewEntity().Get(&event)
-	default:
-	}
+	w.Run(30)
 }
 
-func (s *InputConsoleSystem) readConsoleInput() {
+// readConsoleInput reads the console keys and sends them to the world as the InputEvent events.
+func readConsoleInput(w ecs.World, events *ecs.Events[InputEvent]) {
 	for {
 		// nolint: exhaustive
 		switch ev := term.PollEvent(); ev.Type {
 		case term.EventKey:
-			s.lastPressed <- ev.Key
+			event := InputEvent{}
+
+			// nolint: exhaustive
+			switch ev.Key {
+			case term.KeyCtrlC, term.KeyEsc:
+				w.Stop()
+				return
+
+			case term.KeyArrowUp:
+				event.Vertical = 1
+			case term.KeyArrowDown:
+				event.Vertical = -1
+			case term.KeyArrowLeft:
+				event.Horizontal = -1
+			case term.KeyArrowRight:
+				event.Horizontal = 1
+			}
+
+			if event.Vertical == 0 && event.Horizontal == 0 {
+				continue
+			}
+
+			events.Send(event)
 		case term.EventError:
 			panic(ev.Err)
 		}
@@ -93,44 +69,40 @@ func (s *InputConsoleSystem) readConsoleInput() {
 }
 
 type MovePlayerSystem struct {
+	input *ecs.EventReader[InputEvent]
 }
 
 func (s *MovePlayerSystem) GetFilters() []ecs.SystemFilter {
 	return []ecs.SystemFilter{
-		{Include: []ecs.Component{(*InputEvent)(nil)}},
 		{Include: []ecs.Component{(*Player)(nil), (*Position)(nil)}},
 	}
 }
 
-func (s *MovePlayerSystem) Update(_ float32, filtered [][]ecs.Entity) {
-	input := filtered[0]
-	players := filtered[1]
+func (s *MovePlayerSystem) Update(_ time.Duration, filtered [][]ecs.Entity) {
+	players := filtered[0]
 
-	if len(input) == 0 {
-		return
-	}
-
-	ie := input[0].Get((*InputEvent)(nil)).(*InputEvent)
-	for _, p := range players {
-		pos := p.Get((*Position)(nil)).(*Position)
-		pos.X += ie.Horizontal
-		pos.Y -= ie.Vertical
+	for _, ie := range s.input.Read() {
+		for _, p := range players {
+			pos := ecs.Get[*Position](p)
+			pos.X += ie.Horizontal
+			pos.Y -= ie.Vertical
+		}
 	}
 }
 
 type RenderConsoleSystem struct {
+	input       *ecs.EventReader[InputEvent]
 	notFirstRun bool
 }
 
 func (s *RenderConsoleSystem) GetFilters() []ecs.SystemFilter {
 	return []ecs.SystemFilter{
 		{Include: []ecs.Component{(*Position)(nil), (*RenderConsole)(nil)}},
-		{Include: []ecs.Component{(*InputEvent)(nil)}},
 	}
 }
 
-func (s *RenderConsoleSystem) Update(_ float32, filtered [][]ecs.Entity) {
-	if len(filtered[1]) == 0 && s.notFirstRun {
+func (s *RenderConsoleSystem) Update(_ time.Duration, filtered [][]ecs.Entity) {
+	if len(s.input.Read()) == 0 && s.notFirstRun {
 		return
 	}
 	s.notFirstRun = true
@@ -138,8 +110,8 @@ func (s *RenderConsoleSystem) Update(_ float32, filtered [][]ecs.Entity) {
 	_ = term.Clear(term.ColorDefault, term.ColorDefault)
 
 	for _, e := range filtered[0] {
-		pos := e.Get((*Position)(nil)).(*Position)
-		char := e.Get((*RenderConsole)(nil)).(*RenderConsole).Char
+		pos := ecs.Get[*Position](e)
+		char := ecs.Get[*RenderConsole](e).Char
 
 		fmt.Println(pos.X, pos.Y, string(char))
 		term.SetCell(pos.X, pos.Y, char, term.ColorDefault, term.ColorGreen)
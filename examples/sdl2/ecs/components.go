@@ -23,11 +23,12 @@ type Position struct {
 // Events
 
 type InputEvent struct {
+	Player gecs.Entity
 	Vector2
 }
 
 type CollideEvent struct {
-	Entities []gecs.Entity
+	A, B gecs.Entity
 }
 
 // Render
@@ -2,6 +2,7 @@ package ecs
 
 import (
 	"image/color"
+	"time"
 
 	"github.com/ghostiam/gecs"
 )
@@ -9,15 +10,19 @@ import (
 func Run() error {
 	windowSize := Size{Width: 800, Height: 600}
 
-	w := gecs.NewWorld()
+	w := gecs.NewWorld(gecs.WithFixedTimestep(time.Second/60, 5))
 
 	w.AddSystem(&InputSystem{w: w})
-	w.AddSystem(&MoveSystem{Velocity: 300, Bounds: Bounds{Size: windowSize}})
-	w.AddSystem(&CollideSystem{})
-	w.AddSystem(&CollectSystem{})
-	w.AddSystem(&RenderSystem{Title: "ECS example", Size: windowSize})
-	w.AddSystem(gecs.NewOneFrame((*InputEvent)(nil)))
-	w.AddSystem(gecs.NewOneFrame((*CollideEvent)(nil)))
+	w.SetResource(&Bounds{Size: windowSize})
+
+	w.AddSystem(&MoveSystem{w: w, Velocity: 300, input: gecs.NewEventReader[InputEvent](w)})
+	w.AddSystem(&CollideSystem{events: gecs.EventsOf[CollideEvent](w)})
+	w.AddSystem(&CollectSystem{collisions: gecs.NewEventReader[CollideEvent](w)})
+	w.AddSystem(&RenderSystem{
+		Title:      "ECS example",
+		Size:       windowSize,
+		collisions: gecs.NewEventReader[CollideEvent](w),
+	}, gecs.VariableRate())
 
 	{
 		player := w.NewEntity()
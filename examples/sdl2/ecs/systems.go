@@ -74,137 +74,113 @@ func (s *InputSystem) Update(_ time.Duration, filtered [][]gecs.Entity) {
 		return
 	}
 
-	player.Replace(&InputEvent{Vector2: s.lastInput})
+	gecs.Send(s.w, InputEvent{Player: player, Vector2: s.lastInput})
 }
 
 type MoveSystem struct {
+	w        gecs.World
 	Velocity int
-	Bounds   Bounds
+
+	input *gecs.EventReader[InputEvent]
 }
 
 func (s *MoveSystem) GetFilters() []gecs.SystemFilter {
-	return []gecs.SystemFilter{
-		{Include: []gecs.Component{(*InputEvent)(nil), (*Player)(nil), (*Position)(nil)}},
-	}
+	return nil
 }
 
-func (s *MoveSystem) Update(delta time.Duration, filtered [][]gecs.Entity) {
+func (s *MoveSystem) Update(delta time.Duration, _ [][]gecs.Entity) {
 	dts := delta.Seconds()
-	players := filtered[0]
-	for _, p := range players {
-		input := p.Get((*InputEvent)(nil)).(*InputEvent)
-		pos := p.Get((*Position)(nil)).(*Position)
+	bounds := gecs.Resource[*Bounds](s.w)
+
+	for _, input := range s.input.Read() {
+		pos := gecs.Get[*Position](input.Player)
+		if pos == nil {
+			continue
+		}
+
 		pos.X += int(float64(s.Velocity)*dts) * input.X
 		pos.Y += int(float64(s.Velocity)*dts) * input.Y
 
-		if s.Bounds.X >= pos.X {
-			pos.X = s.Bounds.X
+		if bounds.X >= pos.X {
+			pos.X = bounds.X
 		}
-		if s.Bounds.Y >= pos.Y {
-			pos.Y = s.Bounds.Y
+		if bounds.Y >= pos.Y {
+			pos.Y = bounds.Y
 		}
 
-		if s.Bounds.Width <= pos.X {
-			pos.X = s.Bounds.Width
+		if bounds.Width <= pos.X {
+			pos.X = bounds.Width
 		}
-		if s.Bounds.Height <= pos.Y {
-			pos.Y = s.Bounds.Height
+		if bounds.Height <= pos.Y {
+			pos.Y = bounds.Height
 		}
 	}
 }
 
-type CollideSystem struct{}
+type CollideSystem struct {
+	events *gecs.Events[CollideEvent]
+}
 
 func (s *CollideSystem) GetFilters() []gecs.SystemFilter {
 	return []gecs.SystemFilter{
-		{Include: []gecs.Component{(*BoxCollider)(nil), (*Position)(nil)}},
-		{Include: []gecs.Component{(*CircleCollider)(nil), (*Position)(nil)}},
+		{
+			Include: []gecs.Component{(*Position)(nil)},
+			AnyOf:   []gecs.Component{(*BoxCollider)(nil), (*CircleCollider)(nil)},
+		},
 	}
 }
 
 func (s *CollideSystem) Update(_ time.Duration, filtered [][]gecs.Entity) {
-	boxes := filtered[0]
-	circles := filtered[1]
+	colliders := filtered[0]
 
-	for _, a := range boxes {
-		aBox := a.Get((*BoxCollider)(nil)).(*BoxCollider)
-		aPos := a.Get((*Position)(nil)).(*Position)
-		aRect := aBox.ToRect(aPos)
-		ace := a.Get(&CollideEvent{}).(*CollideEvent)
-
-	boxesLoop:
-		for _, b := range boxes {
-			if a.ID() == b.ID() {
+	for i, a := range colliders {
+		for _, b := range colliders[i+1:] {
+			if !collide(a, b) {
 				continue
 			}
 
-			for _, exist := range ace.Entities {
-				if exist.ID() == b.ID() {
-					continue boxesLoop
-				}
-			}
-
-			bBox := b.Get((*BoxCollider)(nil)).(*BoxCollider)
-			bPos := b.Get((*Position)(nil)).(*Position)
-			bRect := bBox.ToRect(bPos)
-
-			if aRect.HasIntersection(bRect) {
-				ace.Entities = append(ace.Entities, b)
-
-				bce := b.Get(&CollideEvent{}).(*CollideEvent)
-				bce.Entities = append(bce.Entities, a)
-			}
+			s.events.Send(CollideEvent{A: a, B: b})
 		}
 	}
+}
 
-	for _, a := range circles {
-		aBox := a.Get((*CircleCollider)(nil)).(*CircleCollider)
-		aPos := a.Get((*Position)(nil)).(*Position)
-		aCircle := aBox.ToCircle(aPos)
-		ace := a.Get(&CollideEvent{}).(*CollideEvent)
-
-	circlesLoop:
-		for _, b := range boxes {
-			if a.ID() == b.ID() {
-				continue
-			}
-
-			for _, exist := range ace.Entities {
-				if exist.ID() == b.ID() {
-					continue circlesLoop
-				}
-			}
-
-			bBox := b.Get((*BoxCollider)(nil)).(*BoxCollider)
-			bPos := b.Get((*Position)(nil)).(*Position)
-			bRect := bBox.ToRect(bPos)
-
-			if aCircle.HasIntersectionWithRect(bRect) {
-				ace.Entities = append(ace.Entities, b)
-
-				bce := b.Get(&CollideEvent{}).(*CollideEvent)
-				bce.Entities = append(bce.Entities, a)
-			}
-		}
+func collide(a, b gecs.Entity) bool {
+	aPos := gecs.Get[*Position](a)
+	bPos := gecs.Get[*Position](b)
+
+	// ToRect and ToCircle return nil if the entity doesn't have such collider.
+	aRect := gecs.Get[*BoxCollider](a).ToRect(aPos)
+	bRect := gecs.Get[*BoxCollider](b).ToRect(bPos)
+	aCircle := gecs.Get[*CircleCollider](a).ToCircle(aPos)
+	bCircle := gecs.Get[*CircleCollider](b).ToCircle(bPos)
+
+	switch {
+	case aRect != nil && bRect != nil:
+		return aRect.HasIntersection(bRect)
+	case aCircle != nil && bRect != nil:
+		return aCircle.HasIntersectionWithRect(bRect)
+	case aRect != nil && bCircle != nil:
+		return bCircle.HasIntersectionWithRect(aRect)
 	}
+
+	return false
 }
 
-type CollectSystem struct{}
+type CollectSystem struct {
+	collisions *gecs.EventReader[CollideEvent]
+}
 
 func (s *CollectSystem) GetFilters() []gecs.SystemFilter {
-	return []gecs.SystemFilter{
-		{Include: []gecs.Component{(*Collectable)(nil), (*CollideEvent)(nil)}},
-	}
+	return nil
 }
 
-func (s *CollectSystem) Update(_ time.Duration, filtered [][]gecs.Entity) {
-	for _, c := range filtered[0] {
-		cc := c.Get((*CollideEvent)(nil)).(*CollideEvent)
-		for _, e := range cc.Entities {
-			if e.Has((*Player)(nil)) {
-				c.Destroy()
-				break
-			}
+func (s *CollectSystem) Update(_ time.Duration, _ [][]gecs.Entity) {
+	for _, ev := range s.collisions.Read() {
+		switch {
+		case gecs.Has[*Player](ev.A) && gecs.Has[*Collectable](ev.B):
+			ev.B.Destroy()
+		case gecs.Has[*Player](ev.B) && gecs.Has[*Collectable](ev.A):
+			ev.A.Destroy()
 		}
 	}
 }
@@ -219,6 +195,8 @@ type RenderSystem struct {
 	dtSum      time.Duration
 	frameCount int
 	lastFPS    float64
+
+	collisions *gecs.EventReader[CollideEvent]
 }
 
 func (s *RenderSystem) Init() error {
@@ -258,6 +236,12 @@ func (s *RenderSystem) Update(delta time.Duration, filtered [][]gecs.Entity) {
 	boxes := filtered[0]
 	circles := filtered[1]
 
+	colliding := make(map[gecs.Entity]bool)
+	for _, ev := range s.collisions.Read() {
+		colliding[ev.A] = true
+		colliding[ev.B] = true
+	}
+
 	s.dtSum += delta
 	s.frameCount++
 
@@ -281,11 +265,10 @@ func (s *RenderSystem) Update(delta time.Duration, filtered [][]gecs.Entity) {
 		_ = s.renderer.FillRect(&sdl.Rect{0, 0, width, height})
 
 		for _, c := range boxes {
-			pos := c.Get((*Position)(nil)).(*Position)
-			r := c.Get((*RenderBox)(nil)).(*RenderBox)
-			cc, ok := c.Get((*CollideEvent)(nil)).(*CollideEvent)
+			pos := gecs.Get[*Position](c)
+			r := gecs.Get[*RenderBox](c)
 
-			if ok && len(cc.Entities) > 0 {
+			if colliding[c] {
 				_ = s.renderer.SetDrawColor(0, 0, 255, 255)
 			} else {
 				_ = s.renderer.SetDrawColor(r.R, r.G, r.B, r.A)
@@ -294,11 +277,10 @@ func (s *RenderSystem) Update(delta time.Duration, filtered [][]gecs.Entity) {
 		}
 
 		for _, c := range circles {
-			pos := c.Get((*Position)(nil)).(*Position)
-			r := c.Get((*RenderCircle)(nil)).(*RenderCircle)
-			cc, ok := c.Get((*CollideEvent)(nil)).(*CollideEvent)
+			pos := gecs.Get[*Position](c)
+			r := gecs.Get[*RenderCircle](c)
 
-			if ok && len(cc.Entities) > 0 {
+			if colliding[c] {
 				_ = s.renderer.SetDrawColor(0, 255, 255, 255)
 			} else {
 				_ = s.renderer.SetDrawColor(r.R, r.G, r.B, r.A)
@@ -1,6 +1,6 @@
 module github.com/ghostiam/gecs/examples/sdl2
 
-go 1.17
+go 1.18
 
 replace github.com/ghostiam/gecs v0.0.0-20211219234822-d9cf0f8f1681 => ../../
 
@@ -15,9 +15,10 @@ func main() {
 	rnd := rand.New(rand.NewSource(time.Now().Unix()))
 
 	w := gecs.NewWorld()
+	w.SetResource(rnd)
 
 	// Add systems
-	w.AddSystem(&RandomMoveSystem{Rnd: rnd, MaxX: width, MaxY: height})
+	w.AddSystem(&RandomMoveSystem{W: w, MaxX: width, MaxY: height})
 	w.AddSystem(&TextRenderSystem{BorderChar: '#', Width: width, Height: height})
 
 	// Create entities
@@ -42,7 +43,7 @@ type TextRender struct {
 }
 
 type RandomMoveSystem struct {
-	Rnd  *rand.Rand
+	W    gecs.World
 	MaxX int
 	MaxY int
 }
@@ -53,13 +54,14 @@ func (s *RandomMoveSystem) GetFilters() []gecs.SystemFilter {
 	}
 }
 
-func (s *RandomMoveSystem) Update(_ time.Duration, filtered [][]gecs.Entity) {
-	positions := filtered[0]
+func (s *RandomMoveSystem) UpdateRows(_ time.Duration, rows [][]gecs.Row) {
+	positions := rows[0]
+	rnd := gecs.Resource[*rand.Rand](s.W)
 
 	for _, p := range positions {
-		pos := p.Get((*Position)(nil)).(*Position)
-		pos.X += s.Rnd.Intn(3) - 1
-		pos.Y -= s.Rnd.Intn(3) - 1
+		pos := gecs.ComponentAt[*Position](p, 0)
+		pos.X += rnd.Intn(3) - 1
+		pos.Y -= rnd.Intn(3) - 1
 
 		// Check bounds
 		if pos.X < 0 {
@@ -89,7 +91,7 @@ func (s *TextRenderSystem) GetFilters() []gecs.SystemFilter {
 	}
 }
 
-func (s *TextRenderSystem) Update(_ time.Duration, filtered [][]gecs.Entity) {
+func (s *TextRenderSystem) UpdateRows(_ time.Duration, rows [][]gecs.Row) {
 	width, height := s.Width, s.Height
 	hasBorder := s.BorderChar != rune(0)
 	if hasBorder {
@@ -99,9 +101,9 @@ func (s *TextRenderSystem) Update(_ time.Duration, filtered [][]gecs.Entity) {
 
 	// Get positions and rune
 	posRune := make(map[int]map[int]rune)
-	for _, entity := range filtered[0] {
-		pos := entity.Get((*Position)(nil)).(*Position)
-		render := entity.Get((*TextRender)(nil)).(*TextRender)
+	for _, row := range rows[0] {
+		pos := gecs.ComponentAt[*Position](row, 0)
+		render := gecs.ComponentAt[*TextRender](row, 1)
 
 		var offset int
 		if hasBorder {
@@ -0,0 +1,317 @@
+package gecs
+
+// filter is a SystemFilter compiled to component ids, with a cache of the archetypes matching it.
+type filter struct {
+	include []componentID
+	exclude []componentID
+	anyOf   []componentID
+	oneOf   []componentID
+	or      []*filter
+
+	optional []componentID
+
+	// Change detection conditions, are checked only in the top-level filter.
+	added   []componentID
+	changed []componentID
+	removed []componentID
+
+	w *world
+
+	archetypes []*archetype
+	// archetypeColumns contains the column indexes of the include and optional components
+	// for every matched archetype, -1 if there is no such column.
+	archetypeColumns [][]int
+
+	// Reusable buffers for the entity and row lists.
+	entities      []Entity
+	rows          []Row
+	rowComponents []Component
+}
+
+// newFilter compiles the SystemFilter and matches it against all existing archetypes.
+func (w *world) newFilter(sf SystemFilter) *filter {
+	f := w.compileFilter(sf)
+	f.added = w.componentTypeIDs(sf.Added)
+	f.changed = w.componentTypeIDs(sf.Changed)
+	f.removed = w.componentTypeIDs(sf.Removed)
+
+	for _, id := range f.removed {
+		w.removedTracked[id] = true
+	}
+
+	for _, a := range w.archetypes {
+		if !a.dropped {
+			f.addArchetype(a)
+		}
+	}
+
+	return f
+}
+
+func (w *world) compileFilter(sf SystemFilter) *filter {
+	f := &filter{
+		include: w.componentTypeIDs(sf.Include),
+		exclude: w.componentTypeIDs(sf.Exclude),
+		anyOf:   w.componentTypeIDs(sf.AnyOf),
+		oneOf:   w.componentTypeIDs(sf.OneOf),
+		w:       w,
+	}
+
+	f.optional = w.componentTypeIDs(sf.Optional)
+
+	for _, nested := range sf.Or {
+		f.or = append(f.or, w.compileFilter(nested))
+	}
+
+	return f
+}
+
+func (w *world) componentTypeIDs(cs []Component) []componentID {
+	var ids []componentID
+	for _, c := range cs {
+		id := w.termID(c)
+		w.pairFiltered(id)
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+// replaceID replaces the component id in all conditions of the filter and its nested filters.
+func (f *filter) replaceID(old, new componentID) {
+	for _, ids := range [][]componentID{f.include, f.exclude, f.anyOf, f.oneOf, f.optional, f.added, f.changed, f.removed} {
+		for i, id := range ids {
+			if id == old {
+				ids[i] = new
+			}
+		}
+	}
+
+	for _, nested := range f.or {
+		nested.replaceID(old, new)
+	}
+}
+
+// match returns true if the archetype matches all filter conditions.
+// A filter without positive conditions matches nothing.
+func (f *filter) match(a *archetype) bool {
+	if len(f.include) == 0 && len(f.anyOf) == 0 && len(f.oneOf) == 0 && len(f.or) == 0 &&
+		len(f.added) == 0 && len(f.changed) == 0 {
+		return false
+	}
+
+	return f.matchConditions(a)
+}
+
+func (f *filter) matchConditions(a *archetype) bool {
+	for _, id := range f.include {
+		if !f.w.archetypeHas(a, id) {
+			return false
+		}
+	}
+
+	for _, id := range f.added {
+		if !f.w.archetypeHas(a, id) {
+			return false
+		}
+	}
+
+	for _, id := range f.changed {
+		if !f.w.archetypeHas(a, id) {
+			return false
+		}
+	}
+
+	for _, id := range f.exclude {
+		if f.w.archetypeHas(a, id) {
+			return false
+		}
+	}
+
+	if len(f.anyOf) > 0 && f.w.countComponents(a, f.anyOf) == 0 {
+		return false
+	}
+
+	if len(f.oneOf) > 0 && f.w.countComponents(a, f.oneOf) != 1 {
+		return false
+	}
+
+	if len(f.or) == 0 {
+		return true
+	}
+
+	for _, nested := range f.or {
+		if nested.matchConditions(a) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// countComponents returns the number of components from the list contained in the archetype.
+func (w *world) countComponents(a *archetype, ids []componentID) int {
+	n := 0
+	for _, id := range ids {
+		if w.archetypeHas(a, id) {
+			n++
+		}
+	}
+
+	return n
+}
+
+// hasChangeConditions returns true if the filter result depends on the tick of the previous update.
+func (f *filter) hasChangeConditions() bool {
+	return len(f.added) > 0 || len(f.changed) > 0 || len(f.removed) > 0
+}
+
+// each calls fn for every entity matching the filter.
+// The change detection conditions are checked against the changes made after the since tick.
+func (f *filter) each(since uint64, fn func(a *archetype, columns []int, row int)) {
+	if len(f.removed) > 0 {
+		f.eachRemoved(since, fn)
+		return
+	}
+
+	for ai, a := range f.archetypes {
+		columns := f.archetypeColumns[ai]
+
+		for row := range a.entities {
+			if !f.changedSince(a, row, since) {
+				continue
+			}
+
+			fn(a, columns, row)
+		}
+	}
+}
+
+// eachRemoved calls fn for every alive entity from the removal log matching the filter.
+func (f *filter) eachRemoved(since uint64, fn func(a *archetype, columns []int, row int)) {
+	seen := make(map[*entity]struct{})
+
+	for _, r := range f.w.removed[f.removed[0]] {
+		e := r.e
+		if r.tick <= since || !f.w.entityAlive(e) {
+			continue
+		}
+
+		if _, ok := seen[e]; ok {
+			continue
+		}
+		seen[e] = struct{}{}
+
+		removed := true
+		for _, id := range f.removed[1:] {
+			if !f.w.removedSince(e, id, since) {
+				removed = false
+				break
+			}
+		}
+
+		if !removed || !f.matchConditions(e.archetype) || !f.changedSince(e.archetype, e.row, since) {
+			continue
+		}
+
+		fn(e.archetype, f.columns(e.archetype), e.row)
+	}
+}
+
+// changedSince returns true if the row matches the Added and Changed conditions.
+func (f *filter) changedSince(a *archetype, row int, since uint64) bool {
+	for _, id := range f.added {
+		if a.added[f.w.archetypeColumn(a, id)][row] <= since {
+			return false
+		}
+	}
+
+	for _, id := range f.changed {
+		if a.changed[f.w.archetypeColumn(a, id)][row] <= since {
+			return false
+		}
+	}
+
+	return true
+}
+
+// columns returns the column indexes of the include and optional components in the archetype.
+func (f *filter) columns(a *archetype) []int {
+	columns := make([]int, 0, len(f.include)+len(f.optional))
+	for _, id := range f.include {
+		columns = append(columns, f.w.archetypeColumn(a, id))
+	}
+	for _, id := range f.optional {
+		columns = append(columns, f.w.archetypeColumn(a, id))
+	}
+
+	return columns
+}
+
+// entityList returns all entities matching the filter.
+// The returned slice is reused on the next call.
+func (f *filter) entityList(since uint64) []Entity {
+	f.entities = f.entities[:0]
+
+	f.each(since, func(a *archetype, _ []int, row int) {
+		f.entities = append(f.entities, a.entities[row])
+	})
+
+	return f.entities
+}
+
+// rowList returns the rows of all entities matching the filter.
+// The returned slice is reused on the next call.
+func (f *filter) rowList(since uint64) []Row {
+	width := len(f.include) + len(f.optional)
+
+	n := 0
+	for _, a := range f.archetypes {
+		n += len(a.entities)
+	}
+
+	if cap(f.rowComponents) < n*width {
+		f.rowComponents = make([]Component, 0, n*width)
+	}
+	f.rowComponents = f.rowComponents[:0]
+	f.rows = f.rows[:0]
+
+	f.each(since, func(a *archetype, columns []int, row int) {
+		start := len(f.rowComponents)
+		for _, col := range columns {
+			var c Component
+			if col >= 0 {
+				c = a.columns[col][row]
+			}
+
+			f.rowComponents = append(f.rowComponents, c)
+		}
+
+		end := len(f.rowComponents)
+		f.rows = append(f.rows, Row{Entity: a.entities[row], Components: f.rowComponents[start:end:end]})
+	})
+
+	return f.rows
+}
+
+// filtersAddArchetype adds the new archetype to the cache of all filters matching it.
+func (w *world) filtersAddArchetype(a *archetype) {
+	for _, e := range w.systems {
+		for _, f := range e.filters {
+			f.addArchetype(a)
+		}
+	}
+
+	for _, q := range w.queries {
+		q.f.addArchetype(a)
+	}
+}
+
+func (f *filter) addArchetype(a *archetype) {
+	if !f.match(a) {
+		return
+	}
+
+	f.archetypes = append(f.archetypes, a)
+	f.archetypeColumns = append(f.archetypeColumns, f.columns(a))
+}
@@ -0,0 +1,100 @@
+package gecs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter_AnyOf(t *testing.T) {
+	w := NewWorld()
+	q := w.Query(SystemFilter{
+		AnyOf: []Component{(*Component1)(nil), (*Component2)(nil)},
+	})
+
+	e1 := w.NewEntity()
+	e1.Replace(&Component1{})
+
+	e2 := w.NewEntity()
+	e2.Replace(&Component2{})
+
+	e3 := w.NewEntity()
+	e3.Replace(&Component1{})
+	e3.Replace(&Component2{})
+
+	e4 := w.NewEntity()
+	e4.Replace(&Component3{})
+
+	require.ElementsMatch(t, []Entity{e1, e2, e3}, q.Entities())
+
+	e1.Delete((*Component1)(nil))
+	require.ElementsMatch(t, []Entity{e2, e3}, q.Entities())
+}
+
+func TestFilter_OneOf(t *testing.T) {
+	w := NewWorld()
+	q := w.Query(SystemFilter{
+		OneOf: []Component{(*Component1)(nil), (*Component2)(nil)},
+	})
+
+	e1 := w.NewEntity()
+	e1.Replace(&Component1{})
+
+	e2 := w.NewEntity()
+	e2.Replace(&Component2{})
+
+	e3 := w.NewEntity()
+	e3.Replace(&Component1{})
+	e3.Replace(&Component2{})
+
+	require.ElementsMatch(t, []Entity{e1, e2}, q.Entities())
+
+	e3.Delete((*Component2)(nil))
+	require.ElementsMatch(t, []Entity{e1, e2, e3}, q.Entities())
+}
+
+func TestFilter_Or(t *testing.T) {
+	w := NewWorld()
+
+	// Component3 AND (Component1 OR NOT Component2)
+	q := w.Query(SystemFilter{
+		Include: []Component{(*Component3)(nil)},
+		Or: []SystemFilter{
+			{Include: []Component{(*Component1)(nil)}},
+			{Exclude: []Component{(*Component2)(nil)}},
+		},
+	})
+
+	e1 := w.NewEntity()
+	e1.Replace(&Component3{})
+	e1.Replace(&Component1{})
+	e1.Replace(&Component2{})
+
+	e2 := w.NewEntity()
+	e2.Replace(&Component3{})
+
+	e3 := w.NewEntity()
+	e3.Replace(&Component3{})
+	e3.Replace(&Component2{})
+
+	e4 := w.NewEntity()
+	e4.Replace(&Component1{})
+
+	require.ElementsMatch(t, []Entity{e1, e2}, q.Entities())
+
+	e3.Replace(&Component1{})
+	e1.Delete((*Component1)(nil))
+	require.ElementsMatch(t, []Entity{e2, e3}, q.Entities())
+}
+
+func TestFilter_WithoutPositiveConditions(t *testing.T) {
+	w := NewWorld()
+	q := w.Query(SystemFilter{
+		Exclude: []Component{(*Component2)(nil)},
+	})
+
+	e := w.NewEntity()
+	e.Replace(&Component1{})
+
+	require.Equal(t, 0, q.Len())
+}
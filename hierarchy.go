@@ -0,0 +1,152 @@
+package gecs
+
+import (
+	"reflect"
+)
+
+// ChildOf is the relation of the child entity to its parent, see SetParent.
+// Use Pair((*ChildOf)(nil), parent) in the filters to match the children of the parent.
+type ChildOf struct{}
+
+var childOfType = reflect.TypeOf((*ChildOf)(nil))
+
+// SetParent makes the entity a child of the parent, replacing its previous parent.
+// The nil parent makes the entity a root. The parent that is the entity itself or its descendant is ignored,
+// so the hierarchy never contains cycles.
+//
+// The child stays alive when the parent is destroyed and becomes a root, use DestroyRecursive to destroy both.
+func SetParent(e Entity, parent Entity) {
+	ee := e.(*entity)
+	w := ee.w
+	w.lock()
+	defer w.unlock()
+
+	w.entityAccessed(ee)
+	if ee.archetype == nil {
+		return
+	}
+
+	id := noComponent
+	if parent != nil {
+		p := w.entity(parent.ID())
+		if p == nil || w.isDescendant(p, ee) {
+			return
+		}
+
+		// The new pair is added first, so the entity is not destroyed WithAutoDestroy on the old pair removal.
+		id = w.componentKeyID(componentKey{t: childOfType, target: p.id, pair: true})
+		ee.set(id, &ChildOf{})
+	}
+
+	for _, old := range append([]componentID(nil), ee.archetype.ids...) {
+		if k := w.componentKey(old); old != id && k.pair && k.t == childOfType {
+			ee.deleteID(old)
+		}
+	}
+}
+
+// Parent returns the parent of the entity, or nil if the entity is a root.
+func Parent(e Entity) Entity {
+	ee := e.(*entity)
+	ee.w.lock()
+	defer ee.w.unlock()
+
+	ee.w.entityAccessed(ee)
+	if p := ee.w.parent(ee); p != nil {
+		return p
+	}
+
+	return nil
+}
+
+// Children returns the direct children of the entity.
+func Children(e Entity) []Entity {
+	ee := e.(*entity)
+	ee.w.lock()
+	defer ee.w.unlock()
+
+	ee.w.entityAccessed(ee)
+
+	var children []Entity
+	for _, c := range ee.w.children(ee) {
+		children = append(children, c)
+	}
+
+	return children
+}
+
+// DestroyRecursive destroys the entity with all its descendants, the children are destroyed before their parents.
+func DestroyRecursive(e Entity) {
+	ee := e.(*entity)
+	ee.w.lock()
+	defer ee.w.unlock()
+
+	ee.w.entityAccessed(ee)
+	if !ee.w.entityAlive(ee) {
+		return
+	}
+
+	// The descendants are collected first, since destroying the parent makes its children roots.
+	var descendants []*entity
+	var collect func(e *entity)
+	collect = func(e *entity) {
+		for _, c := range ee.w.children(e) {
+			collect(c)
+		}
+		descendants = append(descendants, e)
+	}
+	collect(ee)
+
+	for _, d := range descendants {
+		d.destroy()
+	}
+}
+
+// parent returns the parent of the entity, or nil.
+func (w *world) parent(e *entity) *entity {
+	if e.archetype == nil {
+		return nil
+	}
+
+	for _, id := range e.archetype.ids {
+		if k := w.componentKey(id); k.pair && k.t == childOfType {
+			return w.entity(k.target)
+		}
+	}
+
+	return nil
+}
+
+// children returns the direct children of the entity.
+// Only the archetypes with the pairs targeting the entity are checked, see world.pairTargets.
+func (w *world) children(e *entity) []*entity {
+	tp, ok := w.pairTargets[e.id]
+	if !ok {
+		return nil
+	}
+
+	id, ok := w.componentIDs[componentKey{t: childOfType, target: e.id, pair: true}]
+	if !ok {
+		return nil
+	}
+
+	var children []*entity
+	for _, a := range tp.archetypes {
+		if a.has(id) {
+			children = append(children, a.entities...)
+		}
+	}
+
+	return children
+}
+
+// isDescendant returns true if the entity is the ancestor itself or its descendant.
+func (w *world) isDescendant(e, ancestor *entity) bool {
+	for ; e != nil; e = w.parent(e) {
+		if e == ancestor {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,109 @@
+package gecs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHierarchy_SetParent(t *testing.T) {
+	w := NewWorld()
+
+	root := w.NewEntity()
+	child1 := w.NewEntity()
+	child2 := w.NewEntity()
+	grandchild := w.NewEntity()
+
+	SetParent(child1, root)
+	SetParent(child2, root)
+	SetParent(grandchild, child1)
+
+	require.Nil(t, Parent(root))
+	require.Equal(t, root, Parent(child1))
+	require.Equal(t, child1, Parent(grandchild))
+	require.ElementsMatch(t, []Entity{child1, child2}, Children(root))
+	require.Equal(t, []Entity{grandchild}, Children(child1))
+	require.Empty(t, Children(grandchild))
+
+	q := w.Query(SystemFilter{Include: []Component{Pair((*ChildOf)(nil), root)}})
+	defer q.Close()
+	require.ElementsMatch(t, []Entity{child1, child2}, q.Entities())
+
+	t.Run("Reparent", func(t *testing.T) {
+		SetParent(grandchild, child2)
+		require.Equal(t, child2, Parent(grandchild))
+		require.Empty(t, Children(child1))
+		require.Equal(t, []Entity{grandchild}, Children(child2))
+		require.Equal(t, []Entity{child2}, Targets(grandchild, (*ChildOf)(nil)))
+	})
+
+	t.Run("Cycle is ignored", func(t *testing.T) {
+		SetParent(root, grandchild)
+		require.Nil(t, Parent(root))
+
+		SetParent(root, root)
+		require.Nil(t, Parent(root))
+	})
+
+	t.Run("Unset", func(t *testing.T) {
+		SetParent(child2, nil)
+		require.Nil(t, Parent(child2))
+		require.Equal(t, []Entity{child1}, Children(root))
+	})
+
+	t.Run("Parent destroyed", func(t *testing.T) {
+		child2.Destroy()
+		require.True(t, grandchild.Alive())
+		require.Nil(t, Parent(grandchild))
+	})
+}
+
+func TestHierarchy_DestroyRecursive(t *testing.T) {
+	w := NewWorld()
+
+	root := w.NewEntity()
+	child := w.NewEntity()
+	grandchild := w.NewEntity()
+	other := w.NewEntity()
+
+	SetParent(child, root)
+	SetParent(grandchild, child)
+	SetParent(other, w.NewEntity())
+
+	var order []Entity
+	w.OnRemove(Pair((*ChildOf)(nil), root), func(e Entity, _, _ Component) {
+		order = append(order, e)
+	})
+	w.OnRemove(Pair((*ChildOf)(nil), child), func(e Entity, _, _ Component) {
+		order = append(order, e)
+	})
+
+	DestroyRecursive(root)
+
+	require.False(t, root.Alive())
+	require.False(t, child.Alive())
+	require.False(t, grandchild.Alive())
+	require.True(t, other.Alive())
+	require.Equal(t, []Entity{grandchild, child}, order, "the children are destroyed first")
+}
+
+func TestHierarchy_ManyParents(t *testing.T) {
+	w := NewWorld().(*world)
+
+	child := w.NewEntity()
+	for i := 0; i < 100; i++ {
+		root := w.NewEntity()
+		parent := w.NewEntity()
+		SetParent(parent, root)
+		SetParent(child, parent)
+		require.Equal(t, []Entity{child}, Children(parent))
+
+		DestroyRecursive(root)
+		require.False(t, child.Alive())
+
+		child = w.NewEntity()
+	}
+
+	require.Empty(t, w.pairTargets, "the pairs of the destroyed parents should be released")
+	require.LessOrEqual(t, len(w.archetypeIndex), 3)
+}
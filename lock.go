@@ -0,0 +1,25 @@
+package gecs
+
+// lock locks the world in the locking mode, see WithLocking.
+func (w *world) lock() {
+	if w.locking {
+		w.mu.Lock()
+	}
+}
+
+// unlock unlocks the world in the locking mode and calls the observers deferred while it was locked.
+func (w *world) unlock() {
+	if !w.locking {
+		return
+	}
+
+	calls := w.pendingObservers
+	w.pendingObservers = nil
+	w.mu.Unlock()
+
+	for _, call := range calls {
+		for _, fn := range call.fns {
+			fn(call.e, call.old, call.new)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package gecs
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLock_ConcurrentEntities(t *testing.T) {
+	w := NewWorld(WithLocking(), WithParallelSystems())
+
+	s1 := &RowsSystem{}
+	s2 := &AccessSystem{Acc: SystemAccess{Read: []Component{(*Component1)(nil)}}}
+	w.AddSystem(s1)
+	w.AddSystem(s2)
+
+	var added int
+	var mu sync.Mutex
+	w.OnAdd((*Component2)(nil), func(e Entity, _, _ Component) {
+		// The observer is called outside the lock, so it can use the entity.
+		if !e.Has((*Component2)(nil)) {
+			t.Error("component should be added before the observer is called")
+		}
+
+		mu.Lock()
+		added++
+		mu.Unlock()
+	})
+
+	const producers = 4
+	const entities = 100
+
+	var wg sync.WaitGroup
+	wg.Add(producers)
+	for i := 0; i < producers; i++ {
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < entities; j++ {
+				e := w.NewEntity()
+				e.Replace(&Component1{Num: j})
+				e.Replace(&Component2{Text: "Hello world"})
+				e.Delete((*Component1)(nil))
+				_ = w.EntityCount()
+
+				w.Inbox().Spawn(&Component1{Num: j})
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+loop:
+	for {
+		select {
+		case <-done:
+			break loop
+		default:
+			require.NoError(t, w.SystemsUpdate(time.Millisecond))
+		}
+	}
+	require.NoError(t, w.SystemsUpdate(time.Millisecond))
+
+	require.Equal(t, 2*producers*entities, w.EntityCount())
+	require.Equal(t, producers*entities, added)
+	require.Len(t, s2.Filtered[0], producers*entities, "Inbox should be applied at the start of the update")
+}
+
+func TestLock_Inbox(t *testing.T) {
+	w := NewWorld()
+
+	s := &AccessSystem{Acc: SystemAccess{Read: []Component{(*Component1)(nil)}}}
+	w.AddSystem(s)
+
+	w.Inbox().Spawn(&Component1{Num: 1})
+	require.Equal(t, 0, w.EntityCount())
+
+	require.NoError(t, w.SystemsUpdate(time.Second))
+	require.Len(t, s.Filtered[0], 1)
+}
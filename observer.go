@@ -0,0 +1,100 @@
+package gecs
+
+// ComponentObserver is called on the component lifecycle events.
+//    e - the entity whose component is changed.
+//    old - the previous component, nil on add.
+//    new - the new component, nil on remove.
+type ComponentObserver func(e Entity, old, new Component)
+
+// componentObservers contains the observers of one component type.
+type componentObservers struct {
+	onAdd     []ComponentObserver
+	onReplace []ComponentObserver
+	onRemove  []ComponentObserver
+}
+
+func (w *world) OnAdd(c Component, fn ComponentObserver) {
+	w.lock()
+	defer w.unlock()
+
+	o := w.componentObservers(c)
+	o.onAdd = append(o.onAdd, fn)
+}
+
+func (w *world) OnReplace(c Component, fn ComponentObserver) {
+	w.lock()
+	defer w.unlock()
+
+	o := w.componentObservers(c)
+	o.onReplace = append(o.onReplace, fn)
+}
+
+func (w *world) OnRemove(c Component, fn ComponentObserver) {
+	w.lock()
+	defer w.unlock()
+
+	o := w.componentObservers(c)
+	o.onRemove = append(o.onRemove, fn)
+}
+
+func (w *world) componentObservers(c Component) *componentObservers {
+	id := w.termID(c)
+
+	o, ok := w.observers[id]
+	if !ok {
+		o = &componentObservers{}
+		w.observers[id] = o
+	}
+
+	return o
+}
+
+func (w *world) notifyAdd(e *entity, id componentID, c Component) {
+	o, ok := w.observers[id]
+	if !ok {
+		return
+	}
+
+	w.callObservers(o.onAdd, e, nil, c)
+}
+
+func (w *world) notifyReplace(e *entity, id componentID, old, new Component) {
+	o, ok := w.observers[id]
+	if !ok {
+		return
+	}
+
+	w.callObservers(o.onReplace, e, old, new)
+}
+
+func (w *world) notifyRemove(e *entity, id componentID, c Component) {
+	o, ok := w.observers[id]
+	if !ok {
+		return
+	}
+
+	w.callObservers(o.onRemove, e, c, nil)
+}
+
+// observerCall is the observers call deferred until the world is unlocked.
+type observerCall struct {
+	fns      []ComponentObserver
+	e        *entity
+	old, new Component
+}
+
+// callObservers calls the observers, or defers the call until the world is unlocked in the locking mode.
+func (w *world) callObservers(fns []ComponentObserver, e *entity, old, new Component) {
+	if len(fns) == 0 {
+		return
+	}
+
+	if w.locking {
+		w.pendingObservers = append(w.pendingObservers, observerCall{fns: fns, e: e, old: old, new: new})
+		return
+	}
+
+	for _, fn := range fns {
+		fn(e, old, new)
+	}
+}
@@ -0,0 +1,122 @@
+package gecs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type observedEvent struct {
+	Kind     string
+	Entity   Entity
+	Old, New Component
+}
+
+func observe(w World, c Component, events *[]observedEvent) {
+	w.OnAdd(c, func(e Entity, old, new Component) {
+		*events = append(*events, observedEvent{Kind: "add", Entity: e, Old: old, New: new})
+	})
+	w.OnReplace(c, func(e Entity, old, new Component) {
+		*events = append(*events, observedEvent{Kind: "replace", Entity: e, Old: old, New: new})
+	})
+	w.OnRemove(c, func(e Entity, old, new Component) {
+		*events = append(*events, observedEvent{Kind: "remove", Entity: e, Old: old, New: new})
+	})
+}
+
+func TestObserver(t *testing.T) {
+	w := NewWorld()
+
+	var events []observedEvent
+	observe(w, (*Component1)(nil), &events)
+
+	e := w.NewEntity()
+	c1 := &Component1{Num: 1}
+	c2 := &Component1{Num: 2}
+
+	t.Run("Add", func(t *testing.T) {
+		events = nil
+		e.Get(c1)
+		e.Replace(&Component2{}) // not observed
+
+		require.Equal(t, []observedEvent{{Kind: "add", Entity: e, New: c1}}, events)
+	})
+
+	t.Run("Get existing", func(t *testing.T) {
+		events = nil
+		e.Get(c2)
+
+		require.Len(t, events, 0)
+	})
+
+	t.Run("Replace", func(t *testing.T) {
+		events = nil
+		e.Replace(c2)
+		e.Replace((*Component1)(nil))
+
+		require.Equal(t, []observedEvent{{Kind: "replace", Entity: e, Old: c1, New: c2}}, events)
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		events = nil
+		e.Delete((*Component1)(nil))
+		e.Delete((*Component1)(nil))
+
+		require.Equal(t, []observedEvent{{Kind: "remove", Entity: e, Old: c2}}, events)
+	})
+
+	t.Run("Destroy", func(t *testing.T) {
+		e.Replace(c1)
+
+		events = nil
+		var hasOnRemove bool
+		w.OnRemove((*Component1)(nil), func(e Entity, _, _ Component) {
+			hasOnRemove = e.Has((*Component1)(nil))
+		})
+
+		e.Destroy()
+		e.Destroy()
+
+		require.Equal(t, []observedEvent{{Kind: "remove", Entity: e, Old: c1}}, events)
+		require.True(t, hasOnRemove, "Component should be available in OnRemove")
+	})
+}
+
+func TestObserver_DestroyDeletesInObserver(t *testing.T) {
+	w := NewWorld()
+
+	var events []observedEvent
+	observe(w, (*Component2)(nil), &events)
+
+	w.OnRemove((*Component1)(nil), func(e Entity, _, _ Component) {
+		e.Delete((*Component2)(nil))
+	})
+
+	e := w.NewEntity()
+	e.Replace(&Component1{})
+	c2 := e.Get(&Component2{})
+	events = nil
+
+	e.Destroy()
+
+	require.False(t, e.Alive())
+	require.Equal(t, []observedEvent{{Kind: "remove", Entity: e, Old: c2}}, events,
+		"the component deleted by the observer should be notified once")
+}
+
+func TestObserver_AutoDestroy(t *testing.T) {
+	w := NewWorld(WithAutoDestroy())
+
+	var events []observedEvent
+	observe(w, (*Component1)(nil), &events)
+
+	e := w.NewEntity()
+	c := e.Get(&Component1{})
+	e.Delete((*Component1)(nil))
+
+	require.False(t, e.Alive())
+	require.Equal(t, []observedEvent{
+		{Kind: "add", Entity: e, New: c},
+		{Kind: "remove", Entity: e, Old: c},
+	}, events)
+}
@@ -11,8 +11,8 @@ type oneFrame struct {
 // NewOneFrame returns a system that, when called, removes the component from all entities.
 // Takes in a component whose type is to be removed.
 //
-// The use of this system involves adding it at the very end of the list of systems,
-// so that the deletion occurs at the end of the cycle.
+// The system is updated in StageCleanup, so that the deletion occurs at the end of the cycle.
+// The components are deleted through World.SystemCommands, so the system doesn't need the exclusive access.
 func NewOneFrame(c Component) System {
 	return &oneFrame{
 		c: c,
@@ -23,10 +23,23 @@ func (s *oneFrame) GetFilters() []SystemFilter {
 	return []SystemFilter{{Include: []Component{s.c}}}
 }
 
+func (s *oneFrame) Stage() Stage {
+	return StageCleanup
+}
+
+func (s *oneFrame) Access() SystemAccess {
+	return SystemAccess{Read: []Component{s.c}}
+}
+
 func (s *oneFrame) Update(_ time.Duration, filtered [][]Entity) {
 	for _, es := range filtered {
+		if len(es) == 0 {
+			continue
+		}
+
+		cmds := es[0].(*entity).w.SystemCommands(s)
 		for _, e := range es {
-			e.Delete(s.c)
+			cmds.Remove(e, s.c)
 		}
 	}
 }
@@ -24,3 +24,38 @@ func TestSystem_NewOneFrame(t *testing.T) {
 
 	require.False(t, e.Has((*OneFrameComponent)(nil)))
 }
+
+func TestSystem_NewOneFrameMultiple(t *testing.T) {
+	w := NewWorld()
+	w.AddSystem(NewOneFrame((*OneFrameComponent)(nil)))
+	w.AddSystem(NewOneFrame((*Component1)(nil)))
+
+	e := w.NewEntity()
+	e.Replace(&OneFrameComponent{Event: "EventName"})
+	e.Replace(&Component1{Num: 1})
+	e.Replace(&Component2{Text: "Hello world"})
+
+	w.SystemsUpdate(time.Second)
+
+	require.False(t, e.Has((*OneFrameComponent)(nil)))
+	require.False(t, e.Has((*Component1)(nil)))
+	require.True(t, e.Has((*Component2)(nil)))
+}
+
+func TestSystem_NewOneFrameAllEntities(t *testing.T) {
+	w := NewWorld(WithParallelSystems())
+	w.AddSystem(NewOneFrame((*OneFrameComponent)(nil)))
+
+	var es []Entity
+	for i := 0; i < 10; i++ {
+		e := w.NewEntity()
+		e.Replace(&OneFrameComponent{Event: "EventName"})
+		es = append(es, e)
+	}
+
+	w.SystemsUpdate(time.Second)
+
+	for _, e := range es {
+		require.False(t, e.Has((*OneFrameComponent)(nil)))
+	}
+}
@@ -0,0 +1,68 @@
+package gecs
+
+import (
+	"time"
+)
+
+// WorldOption configures the world created by NewWorld.
+type WorldOption func(w *world)
+
+// WithAutoDestroy makes the world destroy an entity when its last component is deleted.
+// By default, an entity exists until Entity.Destroy is called, regardless of the number of its components.
+func WithAutoDestroy() WorldOption {
+	return func(w *world) {
+		w.autoDestroy = true
+	}
+}
+
+// WithParallelSystems makes the world update the systems, which don't access the same components, in parallel.
+// The systems are split into batches by their SystemAccess, the conflicting systems are updated in the order they were added.
+// The systems without the SystemAccessor are exclusive. The systems, which make structural changes directly
+// instead of World.Commands, must declare the exclusive access.
+func WithParallelSystems() WorldOption {
+	return func(w *world) {
+		w.parallel = true
+	}
+}
+
+// WithFixedTimestep makes the world update the systems with the fixed delta.
+// The time passed to SystemsUpdate is accumulated and the systems are updated once per every full step,
+// but not more than maxSteps times per call, the remaining whole steps are dropped, so a slow update doesn't
+// make the following ones even slower. The systems added with the VariableRate option are updated once per call,
+// they can use World.Alpha to interpolate between the fixed updates.
+// The step less than or equal to 0 disables the mode, maxSteps less than 1 is treated as 1.
+func WithFixedTimestep(step time.Duration, maxSteps int) WorldOption {
+	if maxSteps < 1 {
+		maxSteps = 1
+	}
+
+	return func(w *world) {
+		w.fixedStep = step
+		w.fixedMaxSteps = maxSteps
+	}
+}
+
+// WithErrorPolicy sets the policy of handling the SystemTryUpdater.TryUpdate errors, ErrorPolicyStop by default.
+func WithErrorPolicy(p ErrorPolicy) WorldOption {
+	return func(w *world) {
+		w.errorPolicy = p
+	}
+}
+
+// WithPanicHandler makes the world recover the system update panics and pass them to the handler,
+// which decides what to do with the system. Without the handler, the panic is propagated.
+func WithPanicHandler(h PanicHandler) WorldOption {
+	return func(w *world) {
+		w.panicHandler = h
+	}
+}
+
+// WithLocking makes the world goroutine-safe: the Entity methods, the world entity methods, queries
+// and observers registration are guarded by a mutex, so they can be called from other goroutines during the update.
+// The observers are called after the operation completes, outside the lock.
+// The systems management and the world loop methods are still expected to be called from one goroutine.
+func WithLocking() WorldOption {
+	return func(w *world) {
+		w.locking = true
+	}
+}
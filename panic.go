@@ -0,0 +1,82 @@
+package gecs
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"runtime/debug"
+)
+
+// SystemPanic contains the panic recovered from the system update.
+type SystemPanic struct {
+	System System
+	Value  interface{}
+	Stack  []byte
+	// Entity is the last entity accessed by the system before the panic, nil if there is none.
+	// May be inaccurate if the system is updated in parallel with other systems.
+	Entity Entity
+}
+
+func (p *SystemPanic) Error() string {
+	return fmt.Sprintf("%s: panic: %v", reflect.TypeOf(p.System).String(), p.Value)
+}
+
+// PanicAction is the decision of the PanicHandler.
+type PanicAction int
+
+const (
+	// PanicRestart restarts the system: calls Destroy and Init, if the system implements them, and continues the update.
+	PanicRestart PanicAction = iota
+	// PanicQuarantine disables the system, it can be enabled by World.SetSystemEnabled.
+	PanicQuarantine
+	// PanicShutdown stops the update, SystemsUpdate and Run return the *SystemPanic as an error.
+	PanicShutdown
+)
+
+// PanicHandler is called on the system update panic and returns the action to take with the system.
+type PanicHandler func(p *SystemPanic) PanicAction
+
+// recoverSystem recovers the system panic into the error. Must be deferred.
+func (w *world) recoverSystem(e *systemEntry, err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	p := &SystemPanic{System: e.system, Value: r, Stack: debug.Stack()}
+	if accessed, _ := w.accessed.Load().(*entity); accessed != nil {
+		p.Entity = accessed
+	}
+
+	*err = p
+}
+
+// systemPanic handles the system panic with the world panic handler.
+func (w *world) systemPanic(e *systemEntry, p *SystemPanic) {
+	switch w.panicHandler(p) {
+	case PanicRestart:
+		if s, ok := e.system.(SystemDestroyer); ok {
+			s.Destroy()
+		}
+
+		if s, ok := e.system.(SystemIniter); ok {
+			if err := s.Init(); err != nil {
+				log.Printf("gecs: system restart: %s: %v, the system is disabled", reflect.TypeOf(e.system).String(), err)
+				e.disabled = true
+			}
+		}
+	case PanicQuarantine:
+		e.disabled = true
+	default:
+		if w.updateErr == nil {
+			w.updateErr = p
+		}
+	}
+}
+
+// entityAccessed remembers the entity for the SystemPanic, if the world recovers panics.
+func (w *world) entityAccessed(e *entity) {
+	if w.panicHandler != nil {
+		w.accessed.Store(e)
+	}
+}
@@ -0,0 +1,119 @@
+package gecs
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPanic_Shutdown(t *testing.T) {
+	var got *SystemPanic
+	w := NewWorld(WithPanicHandler(func(p *SystemPanic) PanicAction {
+		got = p
+		return PanicShutdown
+	}))
+
+	s := &PanicSystem{}
+	next := &AccessSystem{}
+	w.AddSystem(s)
+	w.AddSystem(next)
+
+	e := w.NewEntity()
+	e.Replace(&Component1{Num: 1})
+
+	err := w.Run(1000)
+
+	var p *SystemPanic
+	require.True(t, errors.As(err, &p))
+	require.Equal(t, got, p)
+	require.Equal(t, s, p.System)
+	require.Equal(t, e, p.Entity)
+	require.NotEmpty(t, p.Stack)
+	require.Contains(t, p.Error(), "*gecs.PanicSystem: panic:")
+	require.Equal(t, 0, next.Updates)
+	require.Equal(t, 1, s.Destroys, "SystemsDestroy should be called")
+}
+
+func TestPanic_Restart(t *testing.T) {
+	w := NewWorld(WithPanicHandler(func(*SystemPanic) PanicAction {
+		return PanicRestart
+	}))
+
+	s := &PanicSystem{}
+	next := &AccessSystem{}
+	w.AddSystem(s)
+	w.AddSystem(next)
+	require.NoError(t, w.SystemsInit())
+
+	w.NewEntity().Replace(&Component1{Num: 1})
+
+	require.NoError(t, w.SystemsUpdate(time.Second))
+	require.Equal(t, 1, next.Updates)
+	require.Equal(t, 1, s.Destroys)
+	require.Equal(t, 2, s.Inits)
+
+	require.NoError(t, w.SystemsUpdate(time.Second))
+	require.Equal(t, 2, s.Updates, "Restarted system should be updated")
+}
+
+func TestPanic_Quarantine(t *testing.T) {
+	w := NewWorld(WithPanicHandler(func(*SystemPanic) PanicAction {
+		return PanicQuarantine
+	}), WithParallelSystems())
+
+	s := &PanicSystem{}
+	other := &AccessSystem{Acc: SystemAccess{Write: []Component{(*Component2)(nil)}}}
+	w.AddSystem(s)
+	w.AddSystem(other)
+
+	w.NewEntity().Replace(&Component1{Num: 1})
+
+	require.NoError(t, w.SystemsUpdate(time.Second))
+	require.NoError(t, w.SystemsUpdate(time.Second))
+	require.Equal(t, 1, s.Updates)
+	require.Equal(t, 2, other.Updates)
+}
+
+func TestPanic_WithoutHandler(t *testing.T) {
+	w := NewWorld()
+	w.AddSystem(&PanicSystem{})
+	w.NewEntity().Replace(&Component1{Num: 1})
+
+	require.Panics(t, func() {
+		_ = w.SystemsUpdate(time.Second)
+	})
+}
+
+// PanicSystem panics on the wrong component type assertion.
+type PanicSystem struct {
+	Inits    int
+	Updates  int
+	Destroys int
+}
+
+func (s *PanicSystem) GetFilters() []SystemFilter {
+	return []SystemFilter{{Include: []Component{(*Component1)(nil)}}}
+}
+
+func (s *PanicSystem) Access() SystemAccess {
+	return SystemAccess{Read: []Component{(*Component1)(nil)}}
+}
+
+func (s *PanicSystem) Init() error {
+	s.Inits++
+	return nil
+}
+
+func (s *PanicSystem) Update(_ time.Duration, filtered [][]Entity) {
+	s.Updates++
+
+	for _, e := range filtered[0] {
+		_ = e.Get((*Component1)(nil)).(*Component2)
+	}
+}
+
+func (s *PanicSystem) Destroy() {
+	s.Destroys++
+}
@@ -0,0 +1,76 @@
+package gecs
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// IgnorePause makes the system update while the world is paused, e.g. for input handling and rendering.
+// While paused, such systems are updated once per World.SystemsUpdate call regardless of the fixed timestep mode.
+func IgnorePause() SystemOption {
+	return func(e *systemEntry) {
+		e.ignorePause = true
+	}
+}
+
+func pauseIgnoringSystems(e *systemEntry) bool {
+	return e.ignorePause
+}
+
+func (w *world) Pause() {
+	atomic.StoreInt32(&w.paused, 1)
+}
+
+func (w *world) Resume() {
+	atomic.StoreInt32(&w.paused, 0)
+	atomic.StoreInt32(&w.stepping, 0)
+}
+
+func (w *world) Paused() bool {
+	return atomic.LoadInt32(&w.paused) == 1
+}
+
+func (w *world) Step() {
+	if w.Paused() {
+		atomic.StoreInt32(&w.stepping, 1)
+	}
+}
+
+// takeStep returns true once after Step is called.
+func (w *world) takeStep() bool {
+	return atomic.CompareAndSwapInt32(&w.stepping, 1, 0)
+}
+
+// systemsStep updates all systems once, without the fixed timestep accumulation.
+func (w *world) systemsStep(delta time.Duration) {
+	if w.fixedStep <= 0 {
+		w.systemsUpdatePass(delta, allSystems)
+		return
+	}
+
+	w.systemsUpdatePass(w.fixedStep, fixedRateSystems)
+	w.systemsUpdatePass(delta, variableRateSystems)
+}
+
+func (w *world) SetTimeScale(scale float64) {
+	if scale < 0 {
+		scale = 0
+	}
+
+	atomic.StoreUint64(&w.timeScale, math.Float64bits(scale))
+}
+
+func (w *world) TimeScale() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&w.timeScale))
+}
+
+// scaleDelta applies the time scale to the delta.
+func (w *world) scaleDelta(delta time.Duration) time.Duration {
+	scale := w.TimeScale()
+	if scale == 1 {
+		return delta
+	}
+
+	return time.Duration(float64(delta) * scale)
+}
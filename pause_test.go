@@ -0,0 +1,90 @@
+package gecs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPause_PauseResume(t *testing.T) {
+	w := NewWorld()
+
+	sim := &AccessSystem{}
+	ui := &AccessSystem2{}
+	w.AddSystem(sim)
+	w.AddSystem(ui, IgnorePause())
+
+	w.Pause()
+	require.True(t, w.Paused())
+
+	w.SystemsUpdate(time.Second)
+	require.Equal(t, 0, sim.Updates)
+	require.Equal(t, 1, ui.Updates)
+
+	t.Run("Step", func(t *testing.T) {
+		w.Step()
+		w.SystemsUpdate(time.Second)
+		require.Equal(t, 1, sim.Updates)
+		require.Equal(t, 2, ui.Updates)
+
+		w.SystemsUpdate(time.Second)
+		require.Equal(t, 1, sim.Updates, "Step should update only once")
+		require.Equal(t, 3, ui.Updates)
+	})
+
+	t.Run("Resume", func(t *testing.T) {
+		w.Resume()
+		require.False(t, w.Paused())
+
+		w.SystemsUpdate(time.Second)
+		require.Equal(t, 2, sim.Updates)
+		require.Equal(t, 4, ui.Updates)
+	})
+
+	t.Run("Step is ignored while not paused", func(t *testing.T) {
+		w.Step()
+		w.Pause()
+		w.SystemsUpdate(time.Second)
+		require.Equal(t, 2, sim.Updates)
+	})
+}
+
+func TestPause_FixedTimestep(t *testing.T) {
+	w := NewWorld(WithFixedTimestep(10*time.Millisecond, 5))
+
+	fixed := &AccessSystem{}
+	w.AddSystem(fixed)
+
+	w.Pause()
+	w.SystemsUpdate(100 * time.Millisecond)
+	require.Equal(t, 0, fixed.Updates)
+
+	w.Step()
+	w.SystemsUpdate(100 * time.Millisecond)
+	require.Equal(t, 1, fixed.Updates)
+	require.Equal(t, 10*time.Millisecond, fixed.Delta)
+
+	w.Resume()
+	w.SystemsUpdate(10 * time.Millisecond)
+	require.Equal(t, 2, fixed.Updates, "Paused time should not be accumulated")
+}
+
+func TestPause_TimeScale(t *testing.T) {
+	w := NewWorld()
+
+	s := &AccessSystem{}
+	w.AddSystem(s)
+	require.Equal(t, 1.0, w.TimeScale())
+
+	w.SetTimeScale(0.5)
+	w.SystemsUpdate(time.Second)
+	require.Equal(t, 500*time.Millisecond, s.Delta)
+
+	w.SetTimeScale(2)
+	w.SystemsUpdate(time.Second)
+	require.Equal(t, 2*time.Second, s.Delta)
+
+	w.SetTimeScale(-1)
+	require.Equal(t, 0.0, w.TimeScale())
+}
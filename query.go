@@ -0,0 +1,105 @@
+package gecs
+
+// Query is a live entity set matching the filter.
+// The query uses the same archetype cache as the systems filters, so it is always up to date
+// and doesn't have to be recreated after the world changes.
+type Query interface {
+	// Entities returns a snapshot of the entities matching the filter.
+	// It is safe to change the entities while iterating over the returned slice.
+	// The change detection conditions of the filter are checked against the previous Entities or Each call.
+	Entities() []Entity
+
+	// Len returns the number of entities matching the filter.
+	Len() int
+
+	// Each calls fn for every entity matching the filter.
+	Each(fn func(e Entity))
+
+	// Close removes the query from the world. The closed query is empty.
+	Close()
+}
+
+type query struct {
+	w *world
+	f *filter
+
+	lastTick uint64
+}
+
+func (w *world) Query(sf SystemFilter) Query {
+	w.lock()
+	defer w.unlock()
+
+	return w.query(sf)
+}
+
+func (w *world) query(sf SystemFilter) *query {
+	q := &query{w: w, f: w.newFilter(sf)}
+	w.queries = append(w.queries, q)
+	return q
+}
+
+func (q *query) Entities() []Entity {
+	q.w.lock()
+	defer q.w.unlock()
+
+	return append([]Entity(nil), q.f.entityList(q.since())...)
+}
+
+func (q *query) Len() int {
+	q.w.lock()
+	defer q.w.unlock()
+
+	if q.f.hasChangeConditions() {
+		return len(q.f.entityList(q.lastTick))
+	}
+
+	n := 0
+	for _, a := range q.f.archetypes {
+		n += len(a.entities)
+	}
+
+	return n
+}
+
+func (q *query) Each(fn func(e Entity)) {
+	for _, e := range q.Entities() {
+		fn(e)
+	}
+}
+
+// since returns the tick of the previous query iteration for the change detection conditions.
+// During the update the world tick is not advanced, so the query used by a system doesn't change the ticks
+// of the system changes, and the changes made later in the same tick are not seen, like the system's own changes.
+func (q *query) since() uint64 {
+	if !q.f.hasChangeConditions() {
+		return 0
+	}
+
+	since := q.lastTick
+	q.lastTick = q.w.tick
+	if !q.w.updating {
+		q.w.tick++
+	}
+
+	return since
+}
+
+func (q *query) Close() {
+	q.w.lock()
+	defer q.w.unlock()
+
+	q.close()
+}
+
+func (q *query) close() {
+	for i, qq := range q.w.queries {
+		if qq == q {
+			q.w.queries = append(q.w.queries[:i], q.w.queries[i+1:]...)
+			break
+		}
+	}
+
+	q.f.archetypes = nil
+	q.f.archetypeColumns = nil
+}
@@ -0,0 +1,104 @@
+package gecs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorld_Query(t *testing.T) {
+	w := NewWorld()
+
+	e1 := w.NewEntity()
+	e1.Replace(&Component1{Num: 1})
+
+	q := w.Query(SystemFilter{
+		Include: []Component{(*Component1)(nil)},
+		Exclude: []Component{(*Component2)(nil)},
+	})
+
+	t.Run("Existing entities", func(t *testing.T) {
+		require.Equal(t, 1, q.Len())
+		require.Equal(t, []Entity{e1}, q.Entities())
+	})
+
+	e2 := w.NewEntity()
+	e2.Replace(&Component1{Num: 2})
+	e2.Replace(&Component3{Flag: true})
+
+	t.Run("Entity added after query created", func(t *testing.T) {
+		require.Equal(t, 2, q.Len())
+		require.ElementsMatch(t, []Entity{e1, e2}, q.Entities())
+	})
+
+	t.Run("Excluded component", func(t *testing.T) {
+		e1.Replace(&Component2{Text: "excluded"})
+		require.Equal(t, []Entity{e2}, q.Entities())
+
+		e1.Delete((*Component2)(nil))
+		require.ElementsMatch(t, []Entity{e1, e2}, q.Entities())
+	})
+
+	t.Run("Each", func(t *testing.T) {
+		var got []Entity
+		q.Each(func(e Entity) {
+			got = append(got, e)
+		})
+		require.ElementsMatch(t, []Entity{e1, e2}, got)
+	})
+
+	t.Run("Change entities while iterating", func(t *testing.T) {
+		es := q.Entities()
+		for _, e := range es {
+			e.Delete((*Component1)(nil))
+		}
+		require.Len(t, es, 2)
+		require.Equal(t, 0, q.Len())
+
+		e1.Replace(&Component1{Num: 1})
+		e2.Replace(&Component1{Num: 2})
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		require.Len(t, w.(*world).queries, 1)
+
+		q.Close()
+		require.Len(t, w.(*world).queries, 0)
+		require.Equal(t, 0, q.Len())
+		require.Nil(t, q.Entities())
+	})
+}
+
+func TestQuery_ChangedInsideSystem(t *testing.T) {
+	w := NewWorld()
+
+	e := w.NewEntity()
+	e.Replace(&Component1{Num: 1})
+	e.Replace(&Component2{})
+
+	q := w.Query(SystemFilter{Changed: []Component{(*Component2)(nil)}})
+
+	var seen, queried []int
+	s := &ChangeSystem{Filter: SystemFilter{Changed: []Component{(*Component1)(nil)}}, OnUpdate: func(filtered []Entity) {
+		seen = append(seen, len(filtered))
+		queried = append(queried, len(q.Entities()))
+
+		for _, e := range filtered {
+			e.Replace(&Component1{Num: 2})
+		}
+	}}
+	w.AddSystem(s)
+	w.AddSystem(&AccessSystem{OnUpdate: func() {
+		if len(seen) == 2 {
+			e.MarkChanged((*Component2)(nil))
+		}
+	}})
+
+	for i := 0; i < 4; i++ {
+		require.NoError(t, w.SystemsUpdate(time.Second))
+	}
+
+	require.Equal(t, []int{1, 0, 0, 0}, seen, "the system should not see its own changes")
+	require.Equal(t, []int{1, 0, 1, 0}, queried, "the query should see the changes of other systems once")
+}
@@ -0,0 +1,340 @@
+package gecs
+
+import (
+	"math"
+	"reflect"
+)
+
+// pairTerm is the relationship pair term returned by Pair.
+type pairTerm struct {
+	relation componentType
+	target   uint64
+}
+
+// Pair returns the term of the relationship pair of the relation component type and the target entity.
+// The term can be used in the SystemFilter, the observers and Entity.Has, Entity.Delete and Entity.MarkChanged.
+//
+// A nil relation matches any relation and a nil target matches any target.
+// The wildcards are supported only in the SystemFilter conditions, except Removed, and in Entity.Has.
+// The pairs removed on the target destruction are not seen by the Removed conditions, the pair of the destroyed
+// target matches nothing.
+func Pair(relation Component, target Entity) Component {
+	p := pairTerm{relation: reflect.TypeOf(relation)}
+	if target != nil {
+		p.target = target.ID()
+	}
+
+	return p
+}
+
+// SetPair adds the relationship pair of the relation component and the target entity to the entity,
+// or replaces the relation component if the pair exists.
+// An entity can have several pairs of the same relation type with different targets.
+// The pair is removed automatically when the target entity is destroyed, so a destroyed target is ignored.
+func SetPair(e Entity, relation Component, target Entity) {
+	ee := e.(*entity)
+	ee.w.lock()
+	defer ee.w.unlock()
+
+	ee.w.entityAccessed(ee)
+	if relation == nil || target == nil || ee.archetype == nil || ee.w.entity(target.ID()) == nil {
+		return
+	}
+
+	id := ee.w.componentKeyID(componentKey{t: reflect.TypeOf(relation), target: target.ID(), pair: true})
+	ee.set(id, relation)
+}
+
+// GetPair returns the relation component of the pair, or nil if the entity doesn't have the pair.
+func GetPair(e Entity, relation Component, target Entity) Component {
+	ee := e.(*entity)
+	ee.w.lock()
+	defer ee.w.unlock()
+
+	ee.w.entityAccessed(ee)
+	if target == nil || ee.archetype == nil {
+		return nil
+	}
+
+	id, ok := ee.w.componentIDs[componentKey{t: reflect.TypeOf(relation), target: target.ID(), pair: true}]
+	if !ok {
+		return nil
+	}
+
+	col := ee.archetype.column(id)
+	if col < 0 {
+		return nil
+	}
+
+	return ee.archetype.columns[col][ee.row]
+}
+
+// HasPair returns true if the entity has the pair, the nil relation or target is a wildcard.
+func HasPair(e Entity, relation Component, target Entity) bool {
+	return e.Has(Pair(relation, target))
+}
+
+// RemovePair removes the pair from the entity.
+func RemovePair(e Entity, relation Component, target Entity) {
+	e.Delete(Pair(relation, target))
+}
+
+// Targets returns the targets of the entity pairs with the relation type.
+func Targets(e Entity, relation Component) []Entity {
+	ee := e.(*entity)
+	ee.w.lock()
+	defer ee.w.unlock()
+
+	ee.w.entityAccessed(ee)
+	if ee.archetype == nil {
+		return nil
+	}
+
+	rt := reflect.TypeOf(relation)
+
+	var targets []Entity
+	for _, id := range ee.archetype.ids {
+		k := ee.w.componentKey(id)
+		if !k.pair || k.t != rt {
+			continue
+		}
+
+		if t := ee.w.entity(k.target); t != nil {
+			targets = append(targets, t)
+		}
+	}
+
+	return targets
+}
+
+// termKey returns the key of the component type or the pair term.
+func (w *world) termKey(c Component) componentKey {
+	if p, ok := c.(pairTerm); ok {
+		return componentKey{t: p.relation, target: p.target, pair: true}
+	}
+
+	return componentKey{t: reflect.TypeOf(c)}
+}
+
+// termID returns the id of the component type or the pair term, registering it if necessary.
+func (w *world) termID(c Component) componentID {
+	return w.componentKeyID(w.termKey(c))
+}
+
+// noComponent is the id, which no archetype contains.
+// It replaces the ids of the pairs of the destroyed targets in the filters, so the reused ids don't match them.
+const noComponent componentID = math.MinInt
+
+// targetPairs contains the pairs with the target entity.
+type targetPairs struct {
+	ids        []componentID
+	archetypes []*archetype // the archetypes containing the pairs
+	filtered   bool         // the pairs are used in the filters
+}
+
+// newPairID registers the pair key. The pairs get negative ids, which are reused after their target is destroyed,
+// so the pairs don't grow the column index of the archetypes. Returns noComponent if the target is destroyed.
+func (w *world) newPairID(k componentKey) componentID {
+	// The pair with the destroyed target can't be added to an entity.
+	if k.target != 0 && w.entity(k.target) == nil {
+		return noComponent
+	}
+
+	var id componentID
+	if n := len(w.freePairIDs); n > 0 {
+		id = w.freePairIDs[n-1]
+		w.freePairIDs = w.freePairIDs[:n-1]
+		w.pairKeys[-id-1] = k
+	} else {
+		w.pairKeys = append(w.pairKeys, k)
+		id = -len(w.pairKeys)
+	}
+	w.componentIDs[k] = id
+
+	if k.target != 0 {
+		tp, ok := w.pairTargets[k.target]
+		if !ok {
+			tp = &targetPairs{}
+			w.pairTargets[k.target] = tp
+		}
+		tp.ids = append(tp.ids, id)
+	}
+
+	return id
+}
+
+// pairFiltered marks the pair used in a filter, so its id is replaced in the filters when the target is destroyed.
+func (w *world) pairFiltered(id componentID) {
+	if id >= 0 {
+		return
+	}
+
+	if k := w.componentKey(id); k.target != 0 {
+		w.pairTargets[k.target].filtered = true
+	}
+}
+
+// indexPairArchetype adds the new archetype to the archetypes of the targets of its pairs.
+func (w *world) indexPairArchetype(a *archetype) {
+	for _, id := range a.ids {
+		if id >= 0 {
+			continue
+		}
+
+		tp := w.pairTargets[w.componentKey(id).target]
+		if len(tp.archetypes) == 0 || tp.archetypes[len(tp.archetypes)-1] != a {
+			tp.archetypes = append(tp.archetypes, a)
+		}
+	}
+}
+
+// removeTargetPairs removes the pairs with the target entity from all entities,
+// then drops the archetypes containing them and releases their ids.
+func (w *world) removeTargetPairs(target uint64) {
+	tp, ok := w.pairTargets[target]
+	if !ok {
+		return
+	}
+
+	// The observers may move the entities to other archetypes with the pairs, which are added to the target archetypes.
+	for moved := true; moved; {
+		moved = false
+		for _, a := range append([]*archetype(nil), tp.archetypes...) {
+			for _, e := range append([]*entity(nil), a.entities...) {
+				for _, id := range tp.ids {
+					e.deleteID(id)
+				}
+				moved = true
+			}
+		}
+	}
+
+	delete(w.pairTargets, target)
+
+	for _, a := range tp.archetypes {
+		w.dropArchetype(a, target)
+	}
+
+	for _, id := range tp.ids {
+		w.releasePairID(id, tp.filtered)
+	}
+
+	// The dropped archetypes are removed from the lists, when they make up half of them.
+	if w.dropped*2 > len(w.archetypes) {
+		w.compactArchetypes()
+	}
+}
+
+// dropArchetype removes the empty archetype from the world, except the filters archetype lists.
+func (w *world) dropArchetype(a *archetype, target uint64) {
+	if a.dropped {
+		return
+	}
+	a.dropped = true
+	w.dropped++
+
+	delete(w.archetypeIndex, a.key)
+
+	for id, next := range a.edgesAdd {
+		delete(next.edgesRemove, id)
+	}
+	for id, prev := range a.edgesRemove {
+		delete(prev.edgesAdd, id)
+	}
+	a.edgesAdd = nil
+	a.edgesRemove = nil
+
+	// The archetype may contain the pairs with other targets.
+	for _, id := range a.ids {
+		if id >= 0 {
+			continue
+		}
+
+		k := w.componentKey(id)
+		if k.target == target {
+			continue
+		}
+
+		if tp, ok := w.pairTargets[k.target]; ok {
+			tp.archetypes = removeArchetype(tp.archetypes, a)
+		}
+	}
+}
+
+// releasePairID unregisters the pair of the destroyed target for the id reuse.
+func (w *world) releasePairID(id componentID, filtered bool) {
+	delete(w.componentIDs, w.componentKey(id))
+	w.pairKeys[-id-1] = componentKey{}
+	w.freePairIDs = append(w.freePairIDs, id)
+
+	delete(w.observers, id)
+	delete(w.removed, id)
+	delete(w.removedTracked, id)
+
+	if !filtered {
+		return
+	}
+
+	for _, e := range w.systems {
+		for _, f := range e.filters {
+			f.replaceID(id, noComponent)
+		}
+	}
+	for _, q := range w.queries {
+		q.f.replaceID(id, noComponent)
+	}
+}
+
+// compactArchetypes removes the dropped archetypes from the world and the filters archetype lists.
+func (w *world) compactArchetypes() {
+	w.archetypes = removeDropped(w.archetypes, nil)
+
+	for _, e := range w.systems {
+		for _, f := range e.filters {
+			f.archetypes = removeDropped(f.archetypes, &f.archetypeColumns)
+		}
+	}
+	for _, q := range w.queries {
+		q.f.archetypes = removeDropped(q.f.archetypes, &q.f.archetypeColumns)
+	}
+
+	w.dropped = 0
+}
+
+// removeDropped removes the dropped archetypes from the list in place, along with their columns if passed.
+func removeDropped(as []*archetype, columns *[][]int) []*archetype {
+	n := 0
+	for i, a := range as {
+		if a.dropped {
+			continue
+		}
+
+		as[n] = a
+		if columns != nil {
+			(*columns)[n] = (*columns)[i]
+		}
+		n++
+	}
+
+	for i := n; i < len(as); i++ {
+		as[i] = nil
+	}
+	if columns != nil {
+		*columns = (*columns)[:n]
+	}
+
+	return as[:n]
+}
+
+// removeArchetype removes the archetype from the list in place.
+func removeArchetype(as []*archetype, a *archetype) []*archetype {
+	for i, aa := range as {
+		if aa == a {
+			copy(as[i:], as[i+1:])
+			as[len(as)-1] = nil
+			return as[:len(as)-1]
+		}
+	}
+
+	return as
+}
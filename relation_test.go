@@ -0,0 +1,230 @@
+package gecs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type TestLikes struct {
+	Amount int
+}
+
+type TestOwns struct{}
+
+func TestRelation_Pairs(t *testing.T) {
+	w := NewWorld()
+
+	e := w.NewEntity()
+	alice := w.NewEntity()
+	bob := w.NewEntity()
+
+	SetPair(e, &TestLikes{Amount: 1}, alice)
+	SetPair(e, &TestLikes{Amount: 2}, bob)
+
+	require.Equal(t, &TestLikes{Amount: 1}, GetPair(e, (*TestLikes)(nil), alice))
+	require.Equal(t, &TestLikes{Amount: 2}, GetPair(e, (*TestLikes)(nil), bob))
+	require.Nil(t, GetPair(e, (*TestOwns)(nil), alice))
+	require.ElementsMatch(t, []Entity{alice, bob}, Targets(e, (*TestLikes)(nil)))
+	require.Empty(t, Targets(e, (*TestOwns)(nil)))
+
+	require.True(t, HasPair(e, (*TestLikes)(nil), alice))
+	require.True(t, HasPair(e, (*TestLikes)(nil), nil))
+	require.True(t, HasPair(e, nil, bob))
+	require.False(t, HasPair(e, (*TestOwns)(nil), nil))
+	require.False(t, HasPair(alice, nil, nil))
+	require.False(t, e.Has((*TestLikes)(nil)), "the pair is not the plain component")
+
+	t.Run("Replace", func(t *testing.T) {
+		SetPair(e, &TestLikes{Amount: 3}, alice)
+		require.Equal(t, &TestLikes{Amount: 3}, GetPair(e, (*TestLikes)(nil), alice))
+		require.Len(t, e.Components(), 2)
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		RemovePair(e, (*TestLikes)(nil), bob)
+		require.False(t, HasPair(e, (*TestLikes)(nil), bob))
+		require.Equal(t, []Entity{alice}, Targets(e, (*TestLikes)(nil)))
+	})
+
+	t.Run("Destroyed target is ignored", func(t *testing.T) {
+		dead := w.NewEntity()
+		dead.Destroy()
+
+		SetPair(e, &TestLikes{}, dead)
+		require.False(t, HasPair(e, (*TestLikes)(nil), dead))
+	})
+}
+
+func TestRelation_Filter(t *testing.T) {
+	w := NewWorld()
+
+	alice := w.NewEntity()
+	bob := w.NewEntity()
+
+	e1 := w.NewEntity()
+	SetPair(e1, &TestLikes{}, alice)
+
+	e2 := w.NewEntity()
+	SetPair(e2, &TestLikes{}, bob)
+	e2.Get(&Component1{})
+
+	e3 := w.NewEntity()
+	SetPair(e3, &TestOwns{}, alice)
+
+	tests := []struct {
+		name   string
+		filter SystemFilter
+		want   []Entity
+	}{
+		{"Exact pair", SystemFilter{Include: []Component{Pair((*TestLikes)(nil), alice)}}, []Entity{e1}},
+		{"Any target", SystemFilter{Include: []Component{Pair((*TestLikes)(nil), nil)}}, []Entity{e1, e2}},
+		{"Any relation", SystemFilter{Include: []Component{Pair(nil, alice)}}, []Entity{e1, e3}},
+		{"Any pair", SystemFilter{Include: []Component{Pair(nil, nil)}}, []Entity{e1, e2, e3}},
+		{
+			"Exclude wildcard",
+			SystemFilter{Include: []Component{Pair(nil, nil)}, Exclude: []Component{Pair((*TestLikes)(nil), nil)}},
+			[]Entity{e3},
+		},
+		{
+			"With component",
+			SystemFilter{Include: []Component{Pair((*TestLikes)(nil), nil), (*Component1)(nil)}},
+			[]Entity{e2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := w.Query(tt.filter)
+			defer q.Close()
+
+			require.ElementsMatch(t, tt.want, q.Entities())
+		})
+	}
+
+	t.Run("Rows", func(t *testing.T) {
+		SetPair(bob, &TestOwns{}, alice)
+
+		s := &OwnsRowsSystem{Target: alice}
+		w.AddSystem(s)
+		defer w.RemoveSystem(s)
+
+		require.NoError(t, w.SystemsUpdate(time.Second))
+		require.Len(t, s.Rows[0], 2)
+		for _, r := range s.Rows[0] {
+			require.Equal(t, &TestOwns{}, ComponentAt[*TestOwns](r, 0))
+		}
+	})
+}
+
+type OwnsRowsSystem struct {
+	Target Entity
+	Rows   [][]Row
+}
+
+func (s *OwnsRowsSystem) GetFilters() []SystemFilter {
+	return []SystemFilter{
+		{Include: []Component{Pair((*TestOwns)(nil), s.Target)}},
+	}
+}
+
+func (s *OwnsRowsSystem) UpdateRows(_ time.Duration, rows [][]Row) {
+	s.Rows = rows
+}
+
+func TestRelation_TargetDestroyed(t *testing.T) {
+	w := NewWorld()
+
+	parent := w.NewEntity()
+	other := w.NewEntity()
+
+	child := w.NewEntity()
+	child.Get(&Component1{})
+	SetPair(child, &TestOwns{}, parent)
+	SetPair(child, &TestOwns{}, other)
+
+	var removed []Component
+	w.OnRemove(Pair((*TestOwns)(nil), parent), func(e Entity, old, new Component) {
+		require.Equal(t, child, e)
+		removed = append(removed, old)
+	})
+
+	q := w.Query(SystemFilter{Include: []Component{Pair((*TestOwns)(nil), parent)}})
+	require.Equal(t, 1, q.Len())
+
+	parent.Destroy()
+
+	require.Equal(t, []Component{&TestOwns{}}, removed)
+	require.True(t, child.Alive())
+	require.False(t, HasPair(child, (*TestOwns)(nil), parent))
+	require.Equal(t, []Entity{other}, Targets(child, (*TestOwns)(nil)))
+	require.True(t, child.Has((*Component1)(nil)))
+	require.Equal(t, 0, q.Len())
+
+	t.Run("AutoDestroy", func(t *testing.T) {
+		w := NewWorld(WithAutoDestroy())
+
+		parent := w.NewEntity()
+		parent.Get(&Component1{})
+		child := w.NewEntity()
+		SetPair(child, &TestOwns{}, parent)
+
+		grandchild := w.NewEntity()
+		SetPair(grandchild, &TestOwns{}, child)
+
+		parent.Delete((*Component1)(nil))
+
+		require.False(t, parent.Alive())
+		require.False(t, child.Alive(), "the entity without the pairs is destroyed")
+		require.False(t, grandchild.Alive())
+	})
+}
+
+func TestRelation_TargetDestroyedReleasesPairs(t *testing.T) {
+	w := NewWorld().(*world)
+
+	e := w.NewEntity()
+	e.Get(&Component1{})
+
+	all := w.Query(SystemFilter{Include: []Component{Pair((*TestLikes)(nil), nil)}})
+	defer all.Close()
+
+	for i := 0; i < 1000; i++ {
+		target := w.NewEntity()
+		SetPair(e, &TestLikes{Amount: i}, target)
+		require.Equal(t, 1, all.Len())
+
+		target.Destroy()
+		require.Equal(t, 0, all.Len())
+	}
+
+	require.Len(t, w.pairKeys, 2, "the pair ids should be reused")
+	require.Len(t, w.componentIDs, 2, "Component1 and the wildcard")
+	require.Empty(t, w.pairTargets)
+	require.LessOrEqual(t, len(w.archetypes), 4)
+	require.LessOrEqual(t, len(w.archetypeIndex), 3)
+	require.LessOrEqual(t, len(all.(*query).f.archetypes), 1)
+
+	t.Run("Filter of the destroyed target doesn't match the reused id", func(t *testing.T) {
+		dead := w.NewEntity()
+		SetPair(e, &TestLikes{}, dead)
+
+		q := w.Query(SystemFilter{Include: []Component{Pair((*TestLikes)(nil), dead)}})
+		defer q.Close()
+		require.Equal(t, 1, q.Len())
+
+		dead.Destroy()
+		require.Equal(t, 0, q.Len())
+
+		alive := w.NewEntity()
+		SetPair(e, &TestLikes{}, alive)
+		require.True(t, HasPair(e, (*TestLikes)(nil), alive))
+		require.Equal(t, 0, q.Len())
+		require.Equal(t, 1, all.Len())
+
+		q2 := w.Query(SystemFilter{Include: []Component{Pair((*TestLikes)(nil), dead)}})
+		defer q2.Close()
+		require.Equal(t, 0, q2.Len())
+	})
+}
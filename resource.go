@@ -0,0 +1,42 @@
+package gecs
+
+import (
+	"reflect"
+)
+
+func (w *world) SetResource(r interface{}) {
+	if r == nil {
+		return
+	}
+
+	w.lock()
+	defer w.unlock()
+
+	w.resources[reflect.TypeOf(r)] = r
+}
+
+func (w *world) RemoveResource(r interface{}) {
+	w.lock()
+	defer w.unlock()
+
+	delete(w.resources, reflect.TypeOf(r))
+}
+
+// Resource returns the world resource of type T, which is the type of the resource passed to World.SetResource.
+// If the world doesn't have the resource, the zero value of T is returned.
+func Resource[T any](w World) T {
+	r, _ := TryResource[T](w)
+	return r
+}
+
+// TryResource returns the world resource of type T and true,
+// or the zero value of T and false if the world doesn't have the resource.
+func TryResource[T any](w World) (T, bool) {
+	ww := w.(*world)
+
+	ww.lock()
+	defer ww.unlock()
+
+	r, ok := ww.resources[reflect.TypeOf((*T)(nil)).Elem()].(T)
+	return r, ok
+}
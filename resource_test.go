@@ -0,0 +1,44 @@
+package gecs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type TestResource struct {
+	Width, Height int
+}
+
+func TestResource_SetRemove(t *testing.T) {
+	w := NewWorld()
+
+	_, ok := TryResource[*TestResource](w)
+	require.False(t, ok)
+	require.Nil(t, Resource[*TestResource](w))
+
+	r := &TestResource{Width: 800, Height: 600}
+	w.SetResource(r)
+	require.Equal(t, r, Resource[*TestResource](w))
+
+	t.Run("Replace", func(t *testing.T) {
+		r2 := &TestResource{Width: 1024, Height: 768}
+		w.SetResource(r2)
+		require.Equal(t, r2, Resource[*TestResource](w))
+	})
+
+	t.Run("Types are distinct", func(t *testing.T) {
+		w.SetResource(TestResource{Width: 1})
+		require.Equal(t, 1, Resource[TestResource](w).Width)
+		require.Equal(t, 1024, Resource[*TestResource](w).Width)
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		w.RemoveResource((*TestResource)(nil))
+		_, ok := TryResource[*TestResource](w)
+		require.False(t, ok)
+
+		_, ok = TryResource[TestResource](w)
+		require.True(t, ok)
+	})
+}
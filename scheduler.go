@@ -0,0 +1,229 @@
+package gecs
+
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
+// SystemAccess describes the components and resources the system accesses during the update.
+//    Read - the components that the system only reads.
+//    Write - the components that the system changes.
+//    ReadResources - the resources that the system only reads, see World.SetResource.
+//    WriteResources - the resources that the system changes.
+//    Exclusive - the system makes structural changes (creates or destroys entities, adds or deletes components)
+//    or accesses the world in another way, so it can't be updated in parallel with any other system.
+type SystemAccess struct {
+	Read           []Component
+	Write          []Component
+	ReadResources  []interface{}
+	WriteResources []interface{}
+	Exclusive      bool
+}
+
+// SystemAccessor ecs interface. Declares the system access for the parallel update.
+// If the system doesn't implement it, the system is exclusive, since any Entity.Get may add a component.
+type SystemAccessor interface {
+	System
+
+	Access() SystemAccess
+}
+
+// typeSet is a set of the component or resource types.
+type typeSet map[reflect.Type]struct{}
+
+func (ts typeSet) add(v interface{}) {
+	ts[reflect.TypeOf(v)] = struct{}{}
+}
+
+// systemAccess is a SystemAccess with the component and resource types sets.
+type systemAccess struct {
+	read           typeSet
+	write          typeSet
+	readResources  typeSet
+	writeResources typeSet
+	allResources   bool // the system doesn't declare the resources it accesses
+	exclusive      bool
+}
+
+func newSystemAccess(s System) *systemAccess {
+	a := &systemAccess{
+		read:           make(typeSet),
+		write:          make(typeSet),
+		readResources:  make(typeSet),
+		writeResources: make(typeSet),
+	}
+
+	sa, ok := s.(SystemAccessor)
+	if !ok {
+		a.exclusive = true
+		a.allResources = true
+
+		return a
+	}
+
+	access := sa.Access()
+	for _, c := range access.Read {
+		a.read.add(c)
+	}
+	for _, c := range access.Write {
+		a.write.add(c)
+	}
+	for _, r := range access.ReadResources {
+		a.readResources.add(r)
+	}
+	for _, r := range access.WriteResources {
+		a.writeResources.add(r)
+	}
+	a.exclusive = access.Exclusive
+
+	return a
+}
+
+// usesResources returns true if the system may access any resource.
+func (a *systemAccess) usesResources() bool {
+	return a.allResources || len(a.readResources) > 0 || len(a.writeResources) > 0
+}
+
+// conflicts returns true if the systems can't be updated in parallel.
+func (a *systemAccess) conflicts(b *systemAccess) bool {
+	if a.exclusive || b.exclusive {
+		return true
+	}
+
+	if (a.allResources && b.usesResources()) || (b.allResources && a.usesResources()) {
+		return true
+	}
+
+	return setsConflict(a.read, a.write, b.read, b.write) ||
+		setsConflict(a.readResources, a.writeResources, b.readResources, b.writeResources)
+}
+
+// setsConflict returns true if one of the systems writes the type, which the other one reads or writes.
+func setsConflict(aRead, aWrite, bRead, bWrite typeSet) bool {
+	for t := range aWrite {
+		if _, ok := bWrite[t]; ok {
+			return true
+		}
+		if _, ok := bRead[t]; ok {
+			return true
+		}
+	}
+
+	for t := range bWrite {
+		if _, ok := aRead[t]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// systemBatches splits the systems into batches, which are updated one after another,
+// the systems of one batch are updated in parallel.
+// A system is placed into the batch after all preceding systems conflicting with it or ordered with it
+// by the stages and constraints, so such systems are always updated in the resolved order.
+func (w *world) systemBatches() [][]*systemEntry {
+	if w.schedule != nil {
+		return w.schedule
+	}
+
+	order, _ := w.systemsOrder()
+	accesses := make([]*systemAccess, len(order))
+	levels := make([]int, len(order))
+
+	for i, e := range order {
+		accesses[i] = newSystemAccess(e.system)
+
+		level := 0
+		for j := 0; j < i; j++ {
+			if levels[j] >= level && (accesses[i].conflicts(accesses[j]) || e.ordered(order[j])) {
+				level = levels[j] + 1
+			}
+		}
+		levels[i] = level
+
+		for len(w.schedule) <= level {
+			w.schedule = append(w.schedule, nil)
+		}
+		w.schedule[level] = append(w.schedule[level], e)
+	}
+
+	return w.schedule
+}
+
+// systemsUpdateParallel updates the systems passing the selector batch by batch.
+func (w *world) systemsUpdateParallel(delta time.Duration, selected func(e *systemEntry) bool) {
+	batches := w.systemBatches()
+	for i, all := range batches {
+		if w.updateErr != nil {
+			return
+		}
+
+		// The stage sync point, the systems of different stages are never in the same batch.
+		if i > 0 && all[0].stage != batches[i-1][0].stage {
+			w.applyCommands()
+		}
+
+		var batch []*systemEntry
+		for _, e := range all {
+			if selected(e) {
+				batch = append(batch, e)
+			}
+		}
+
+		switch len(batch) {
+		case 0:
+			continue
+		case 1:
+			w.systemUpdate(batch[0], delta)
+			continue
+		}
+
+		// The tick is shared by the batch, since its systems don't access the same components.
+		sinces := make([]uint64, len(batch))
+		w.lock()
+		for i, e := range batch {
+			sinces[i] = e.tick
+			e.tick = w.tick
+		}
+		w.unlock()
+
+		errs := make([]error, len(batch))
+		panics := make([]interface{}, len(batch))
+
+		var wg sync.WaitGroup
+		wg.Add(len(batch))
+		for i, e := range batch {
+			go func(i int, e *systemEntry, since uint64) {
+				defer wg.Done()
+				// Without the panic handler the panic is repeated on the calling goroutine,
+				// so that the caller can recover it and the deferred SystemsDestroy is called.
+				defer func() {
+					if r := recover(); r != nil {
+						panics[i] = r
+					}
+				}()
+
+				errs[i] = w.systemUpdateSince(e, delta, since)
+			}(i, e, sinces[i])
+		}
+		wg.Wait()
+
+		for _, p := range panics {
+			if p != nil {
+				panic(p)
+			}
+		}
+
+		for i, err := range errs {
+			if err != nil {
+				w.systemError(batch[i], err)
+			}
+		}
+
+		w.lock()
+		w.tick++
+		w.unlock()
+	}
+}
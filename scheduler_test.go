@@ -0,0 +1,192 @@
+package gecs
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_Batches(t *testing.T) {
+	w := NewWorld(WithParallelSystems()).(*world)
+
+	writer1 := &AccessSystem{Name: "writer1", Acc: SystemAccess{Write: []Component{(*Component1)(nil)}}}
+	reader2 := &AccessSystem2{AccessSystem{Name: "reader2", Acc: SystemAccess{Read: []Component{(*Component2)(nil)}}}}
+	reader1 := &AccessSystem3{AccessSystem{Name: "reader1", Acc: SystemAccess{Read: []Component{(*Component1)(nil), (*Component2)(nil)}}}}
+	exclusive := &AccessSystem4{AccessSystem{Name: "exclusive", Acc: SystemAccess{Exclusive: true}}}
+	undeclared := &Component1System{} // may add components with Entity.Get, so it is exclusive
+	reader3 := &AccessSystem{Name: "reader3", Acc: SystemAccess{Read: []Component{(*Component3)(nil)}}}
+
+	w.AddSystem(writer1)
+	w.AddSystem(reader2)
+	w.AddSystem(reader1)
+	w.AddSystem(exclusive)
+	w.AddSystem(undeclared)
+	w.AddSystem(reader3)
+
+	require.Equal(t, [][]System{
+		{writer1, reader2},
+		{reader1},
+		{exclusive},
+		{undeclared},
+		{reader3},
+	}, batchSystems(w))
+
+	t.Run("Schedule is rebuilt after removal", func(t *testing.T) {
+		w.RemoveSystem(exclusive)
+
+		require.Equal(t, [][]System{
+			{writer1, reader2},
+			{reader1},
+			{undeclared},
+			{reader3},
+		}, batchSystems(w))
+	})
+}
+
+func TestScheduler_Resources(t *testing.T) {
+	w := NewWorld(WithParallelSystems()).(*world)
+
+	writer := &AccessSystem{Acc: SystemAccess{WriteResources: []interface{}{(*TestResource)(nil)}}}
+	reader1 := &AccessSystem2{AccessSystem{Acc: SystemAccess{ReadResources: []interface{}{(*TestResource)(nil)}}}}
+	reader2 := &AccessSystem3{AccessSystem{Acc: SystemAccess{ReadResources: []interface{}{(*TestResource)(nil)}}}}
+	other := &AccessSystem4{AccessSystem{Acc: SystemAccess{WriteResources: []interface{}{TestResource{}}}}}
+	undeclared := &Component1System{}
+
+	w.AddSystem(writer)
+	w.AddSystem(reader1)
+	w.AddSystem(reader2)
+	w.AddSystem(other)
+	w.AddSystem(undeclared)
+
+	require.Equal(t, [][]System{
+		{writer, other},
+		{reader1, reader2},
+		{undeclared},
+	}, batchSystems(w))
+}
+
+func TestScheduler_ParallelUpdate(t *testing.T) {
+	w := NewWorld(WithParallelSystems())
+
+	// Both systems wait for each other, so the update completes only if they are updated in parallel.
+	var barrier sync.WaitGroup
+	barrier.Add(2)
+	wait := func() {
+		barrier.Done()
+
+		done := make(chan struct{})
+		go func() {
+			barrier.Wait()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Error("systems are not updated in parallel")
+		}
+	}
+
+	s1 := &AccessSystem{Acc: SystemAccess{Write: []Component{(*Component1)(nil)}}, OnUpdate: wait}
+	s2 := &AccessSystem2{AccessSystem{Acc: SystemAccess{Write: []Component{(*Component2)(nil)}}, OnUpdate: wait}}
+	w.AddSystem(s1)
+	w.AddSystem(s2)
+
+	e := w.NewEntity()
+	e.Replace(&Component1{})
+	e.Replace(&Component2{})
+
+	w.SystemsUpdate(time.Second)
+	require.Equal(t, 1, s1.Updates)
+	require.Equal(t, 1, s2.Updates)
+	require.Len(t, s1.Filtered[0], 1)
+	require.Len(t, s2.Filtered[0], 1)
+}
+
+func TestScheduler_ConflictOrder(t *testing.T) {
+	w := NewWorld(WithParallelSystems())
+
+	var order []string
+	var mu sync.Mutex
+	record := func(name string) func() {
+		return func() {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, name)
+		}
+	}
+
+	w.AddSystem(&AccessSystem{Acc: SystemAccess{Write: []Component{(*Component1)(nil)}}, OnUpdate: record("first")})
+	w.AddSystem(&AccessSystem2{AccessSystem{Acc: SystemAccess{Read: []Component{(*Component1)(nil)}}, OnUpdate: record("second")}})
+	w.AddSystem(&AccessSystem3{AccessSystem{Acc: SystemAccess{Write: []Component{(*Component1)(nil)}}, OnUpdate: record("third")}})
+
+	for i := 0; i < 10; i++ {
+		order = nil
+		w.SystemsUpdate(time.Second)
+		require.Equal(t, []string{"first", "second", "third"}, order)
+	}
+}
+
+// batchSystems returns the systems of the world batches.
+func batchSystems(w *world) [][]System {
+	var batches [][]System
+	for _, batch := range w.systemBatches() {
+		var ss []System
+		for _, e := range batch {
+			ss = append(ss, e.system)
+		}
+		batches = append(batches, ss)
+	}
+
+	return batches
+}
+
+var _ SystemAccessor = (*AccessSystem)(nil)
+
+type AccessSystem struct {
+	Name     string
+	Acc      SystemAccess
+	OnUpdate func()
+
+	Updates  int
+	Delta    time.Duration
+	Filtered [][]Entity
+}
+
+func (s *AccessSystem) GetFilters() []SystemFilter {
+	var cs []Component
+	cs = append(cs, s.Acc.Read...)
+	cs = append(cs, s.Acc.Write...)
+
+	return []SystemFilter{{Include: cs}}
+}
+
+func (s *AccessSystem) Access() SystemAccess {
+	return s.Acc
+}
+
+func (s *AccessSystem) Update(delta time.Duration, filtered [][]Entity) {
+	s.Updates++
+	s.Delta = delta
+	s.Filtered = filtered
+
+	if s.OnUpdate != nil {
+		s.OnUpdate()
+	}
+}
+
+// Different types to be added to the world along with AccessSystem.
+
+type AccessSystem2 struct {
+	AccessSystem
+}
+
+type AccessSystem3 struct {
+	AccessSystem
+}
+
+type AccessSystem4 struct {
+	AccessSystem
+}
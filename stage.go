@@ -0,0 +1,163 @@
+package gecs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Stage is a group of systems. The stages are updated in the order of their values.
+type Stage int
+
+// Predefined stages, systems are added to StageUpdate by default.
+const (
+	StagePreUpdate Stage = iota
+	StageUpdate
+	StagePostUpdate
+	StageCleanup
+)
+
+func (s Stage) String() string {
+	switch s {
+	case StagePreUpdate:
+		return "PreUpdate"
+	case StageUpdate:
+		return "Update"
+	case StagePostUpdate:
+		return "PostUpdate"
+	case StageCleanup:
+		return "Cleanup"
+	default:
+		return fmt.Sprintf("Stage(%d)", int(s))
+	}
+}
+
+// ErrSystemOrderCycle is returned by World.SystemsInit if the Before and After constraints contain a cycle.
+var ErrSystemOrderCycle = errors.New("system order cycle")
+
+// SystemStager ecs interface. Returns the default stage of the system, InStage option takes precedence over it.
+type SystemStager interface {
+	System
+
+	Stage() Stage
+}
+
+// SystemOption configures the system added by World.AddSystem.
+type SystemOption func(e *systemEntry)
+
+// InStage adds the system to the stage.
+func InStage(stage Stage) SystemOption {
+	return func(e *systemEntry) {
+		e.stage = stage
+	}
+}
+
+// Before makes the system update before the passed one.
+// If the passed system is a nil pointer, e.g. (*MoveSystem)(nil), before all systems of its type.
+func Before(s System) SystemOption {
+	return func(e *systemEntry) {
+		e.before = append(e.before, s)
+	}
+}
+
+// After makes the system update after the passed one.
+// If the passed system is a nil pointer, e.g. (*MoveSystem)(nil), after all systems of its type.
+func After(s System) SystemOption {
+	return func(e *systemEntry) {
+		e.after = append(e.after, s)
+	}
+}
+
+// updatesBefore returns true if the entry must be updated before the other one by the Before and After constraints.
+func (e *systemEntry) updatesBefore(other *systemEntry) bool {
+	for _, s := range e.before {
+		if systemMatches(s, other.system) {
+			return true
+		}
+	}
+
+	for _, s := range other.after {
+		if systemMatches(s, e.system) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ordered returns true if the order of the entries is defined by their stages or constraints.
+func (e *systemEntry) ordered(other *systemEntry) bool {
+	return e.stage != other.stage || e.updatesBefore(other) || other.updatesBefore(e)
+}
+
+// systemsOrder returns the systems in the update order: by stages, then by the Before and After constraints,
+// then in the order they were added.
+// In case of a cycle, the systems are ordered only by stages and an error is returned.
+func (w *world) systemsOrder() ([]*systemEntry, error) {
+	if w.systemOrder != nil {
+		return w.systemOrder, w.systemOrderErr
+	}
+
+	n := len(w.systems)
+
+	// edges[i][j] - the system i must be updated before the system j.
+	edges := make([][]bool, n)
+	indegree := make([]int, n)
+	for i, a := range w.systems {
+		edges[i] = make([]bool, n)
+
+		for j, b := range w.systems {
+			if i != j && (a.stage < b.stage || a.updatesBefore(b)) {
+				edges[i][j] = true
+				indegree[j]++
+			}
+		}
+	}
+
+	order := make([]*systemEntry, 0, n)
+	done := make([]bool, n)
+	for len(order) < n {
+		// The first ready system in the order they were added.
+		next := -1
+		for i := range w.systems {
+			if !done[i] && indegree[i] == 0 {
+				next = i
+				break
+			}
+		}
+
+		if next < 0 {
+			break
+		}
+
+		done[next] = true
+		order = append(order, w.systems[next])
+		for j := range w.systems {
+			if edges[next][j] {
+				indegree[j]--
+			}
+		}
+	}
+
+	w.systemOrder = order
+	w.systemOrderErr = nil
+
+	if len(order) < n {
+		var names []string
+		for i, e := range w.systems {
+			if !done[i] {
+				names = append(names, reflect.TypeOf(e.system).String())
+			}
+		}
+
+		w.systemOrder = append([]*systemEntry(nil), w.systems...)
+		sort.SliceStable(w.systemOrder, func(i, j int) bool {
+			return w.systemOrder[i].stage < w.systemOrder[j].stage
+		})
+		w.systemOrderErr = fmt.Errorf("%w: %s", ErrSystemOrderCycle, strings.Join(names, ", "))
+	}
+
+	return w.systemOrder, w.systemOrderErr
+}
@@ -0,0 +1,105 @@
+package gecs
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStage_Order(t *testing.T) {
+	w := NewWorld()
+
+	var order []string
+	record := func(name string) func() {
+		return func() { order = append(order, name) }
+	}
+
+	w.AddSystem(NewOneFrame((*Component2)(nil)))
+	w.AddSystem(&AccessSystem{OnUpdate: record("cleanup")}, InStage(StageCleanup))
+	w.AddSystem(&AccessSystem2{AccessSystem{OnUpdate: record("update")}})
+	w.AddSystem(&AccessSystem3{AccessSystem{OnUpdate: record("pre")}}, InStage(StagePreUpdate))
+
+	e := w.NewEntity()
+	e.Replace(&Component2{})
+
+	w.SystemsUpdate(time.Second)
+	require.Equal(t, []string{"pre", "update", "cleanup"}, order)
+	require.False(t, e.Has((*Component2)(nil)), "OneFrame should be updated in StageCleanup")
+}
+
+func TestStage_BeforeAfter(t *testing.T) {
+	w := NewWorld()
+
+	var order []string
+	record := func(name string) func() {
+		return func() { order = append(order, name) }
+	}
+
+	w.AddSystem(&AccessSystem{OnUpdate: record("a")}, After((*AccessSystem3)(nil)))
+	b := &AccessSystem2{AccessSystem{OnUpdate: record("b")}}
+	w.AddSystem(b, Before((*AccessSystem)(nil)))
+	w.AddSystem(&AccessSystem3{AccessSystem{OnUpdate: record("c")}})
+
+	require.NoError(t, w.SystemsInit())
+
+	w.SystemsUpdate(time.Second)
+	require.Equal(t, []string{"b", "c", "a"}, order)
+
+	t.Run("Re-added system keeps its position", func(t *testing.T) {
+		b.OnUpdate = record("b2")
+		w.AddSystem(b)
+
+		order = nil
+		w.SystemsUpdate(time.Second)
+		require.Equal(t, []string{"b2", "c", "a"}, order)
+	})
+}
+
+func TestStage_Cycle(t *testing.T) {
+	w := NewWorld()
+
+	w.AddSystem(&AccessSystem{}, Before((*AccessSystem2)(nil)))
+	w.AddSystem(&AccessSystem2{}, Before((*AccessSystem)(nil)))
+	w.AddSystem(&AccessSystem3{}, InStage(StagePreUpdate))
+
+	err := w.SystemsInit()
+	require.True(t, errors.Is(err, ErrSystemOrderCycle))
+	require.Contains(t, err.Error(), "*gecs.AccessSystem")
+
+	t.Run("Cycle across stages", func(t *testing.T) {
+		w := NewWorld()
+
+		w.AddSystem(&AccessSystem{}, InStage(StageCleanup), Before((*AccessSystem2)(nil)))
+		w.AddSystem(&AccessSystem2{})
+
+		require.True(t, errors.Is(w.SystemsInit(), ErrSystemOrderCycle))
+	})
+
+	t.Run("Cycle is resolved after removal", func(t *testing.T) {
+		w.RemoveSystem((*AccessSystem2)(nil))
+		require.NoError(t, w.SystemsInit())
+	})
+}
+
+func TestStage_ParallelBatches(t *testing.T) {
+	w := NewWorld(WithParallelSystems()).(*world)
+
+	s1 := &AccessSystem{Acc: SystemAccess{Write: []Component{(*Component1)(nil)}}}
+	s2 := &AccessSystem2{AccessSystem{Acc: SystemAccess{Write: []Component{(*Component2)(nil)}}}}
+	s3 := &AccessSystem3{AccessSystem{Acc: SystemAccess{Read: []Component{(*Component2)(nil)}}}}
+	s4 := &AccessSystem4{AccessSystem{Acc: SystemAccess{Read: []Component{(*Component2)(nil)}}}}
+
+	w.AddSystem(s1, After((*AccessSystem2)(nil)))
+	w.AddSystem(s2)
+	w.AddSystem(s3, InStage(StagePostUpdate))
+	w.AddSystem(s4, InStage(StagePreUpdate))
+
+	require.Equal(t, [][]System{
+		{s4},
+		{s2},
+		{s1},
+		{s3},
+	}, batchSystems(w))
+}
@@ -8,9 +8,30 @@ import (
 // SystemFilter contains components for filtering entity when calling System.Update method on the system.
 //    Include - the components that should be on the entity.
 //    Exclude - the components of which should not be on the entity.
+//    AnyOf - the components of which at least one should be on the entity.
+//    OneOf - the components of which exactly one should be on the entity.
+//    Or - the nested filters of which at least one should match the entity.
+//    Optional - the components that are passed to SystemRowsUpdater.UpdateRows if they exist, don't affect filtering.
+//    Added - the components that should be added to the entity after the previous system update.
+//    Changed - the components that should be added, replaced or marked as changed after the previous system update.
+//    Removed - the components that should be deleted from the alive entity after the previous system update.
+//    The destroyed entities are not reported, since they can't be passed to the system,
+//    use World.OnRemove to track the components of the destroyed entities, e.g. to update an index.
+// All non-empty conditions must match. The filter without Include, AnyOf, OneOf and Or matches nothing,
+// this doesn't apply to the nested filters, so the nested filter with only Exclude is a valid negation.
+// Added, Changed and Removed are only checked in the top-level filter, the changes made by the system itself are not seen.
 type SystemFilter struct {
 	Include []Component
 	Exclude []Component
+	AnyOf   []Component
+	OneOf   []Component
+	Or      []SystemFilter
+
+	Optional []Component
+
+	Added   []Component
+	Changed []Component
+	Removed []Component
 }
 
 // SystemIniter ecs interface.
@@ -21,9 +42,15 @@ type SystemIniter interface {
 }
 
 // System ecs interface.
+// The system must implement SystemUpdater, SystemTryUpdater or SystemRowsUpdater, otherwise World.AddSystem panics.
 type System interface {
 	// GetFilters returns filters with a list of components.
 	GetFilters() []SystemFilter
+}
+
+// SystemUpdater ecs interface.
+type SystemUpdater interface {
+	System
 
 	// Update is called on every tick.
 	// delta - time elapsed from the previous tick.
@@ -33,155 +60,94 @@ type System interface {
 	Update(delta time.Duration, filtered [][]Entity)
 }
 
-// SystemDestroyer ecs interface.
-type SystemDestroyer interface {
+// SystemTryUpdater ecs interface. An alternative to SystemUpdater, which can report the update failure.
+// The error is handled by the world according to its ErrorPolicy.
+// If the system implements both interfaces, only TryUpdate is called.
+type SystemTryUpdater interface {
 	System
 
-	Destroy()
+	// TryUpdate is called on every tick, the same as SystemUpdater.Update.
+	TryUpdate(delta time.Duration, filtered [][]Entity) error
 }
 
-// SystemFull ecs interface.
-type SystemFull interface {
-	SystemIniter
-	SystemDestroyer
-}
+// SystemRowsUpdater ecs interface. An alternative to SystemUpdater, which receives the matched components
+// instead of the bare entities, so the system doesn't have to look them up again.
+// If the system implements it along with SystemUpdater or SystemTryUpdater, only UpdateRows is called.
+type SystemRowsUpdater interface {
+	System
 
-// systemFilterTypes includes Component types
-type systemFilterTypes struct {
-	Include []reflect.Type
-	Exclude []reflect.Type
+	// UpdateRows is called on every tick.
+	// delta - time elapsed from the previous tick.
+	// rows - filtered rows by filters from the GetFilters method.
+	// Always contains the same number of elements as the GetFilters method returns, in the same filter order.
+	// rows - [FilterIndex][EntityIndex]Row
+	UpdateRows(delta time.Duration, rows [][]Row)
 }
 
-func (w *world) systemCacheDeleteEntityFromAllSystems(e Entity) {
-	for st, fids := range w.systemFiltersEntityCache {
-		for fid := range fids {
-			w.systemCacheDeleteEntityFromSystem(e, st, fid)
-		}
-	}
+// Row contains the entity matched by the filter and its components.
+//    Components - the Include components, followed by the Optional components, in the filter order.
+//    The Optional component is nil if the entity doesn't have it.
+// The rows are reused on the next tick, so they should not be stored.
+type Row struct {
+	Entity     Entity
+	Components []Component
 }
 
-func (w *world) systemCacheDeleteEntityFromSystem(e Entity, systemType reflect.Type, fid filterIndex) {
-	for i, ee := range w.systemFiltersEntityCache[systemType][fid] {
-		if ee.ID() == e.ID() {
-			w.systemFiltersEntityCache[systemType][fid] = append(w.systemFiltersEntityCache[systemType][fid][:i], w.systemFiltersEntityCache[systemType][fid][i+1:]...)
-			break
-		}
-	}
-
-	if len(w.systemFiltersEntityCache[systemType][fid]) == 0 {
-		delete(w.systemFiltersEntityCache[systemType], fid)
-	}
-
-	if len(w.systemFiltersEntityCache[systemType]) == 0 {
-		delete(w.systemFiltersEntityCache, systemType)
-	}
+// ComponentAt returns the component of the row by index with type T.
+// Returns the zero value of T if the component is nil or has a different type.
+func ComponentAt[T Component](r Row, i int) T {
+	c, _ := r.Components[i].(T)
+	return c
 }
 
-func (w *world) systemCacheRebuildByEntity(e Entity) {
-	var componentTypes []reflect.Type
-	for ct, me := range w.components {
-		_, ok := me[e]
-		if !ok {
-			continue
-		}
-
-		componentTypes = append(componentTypes, ct)
-	}
-
-	hasComponentCount := func(ts []reflect.Type) int {
-		found := 0
-
-		for _, c := range componentTypes {
-			for _, t := range ts {
-				if c == t {
-					found++
-				}
-			}
-		}
+// SystemDestroyer ecs interface.
+type SystemDestroyer interface {
+	System
 
-		return found
-	}
+	Destroy()
+}
 
-	for _, s := range w.systems {
-		st := reflect.TypeOf(s)
+// SystemFull ecs interface.
+type SystemFull interface {
+	SystemIniter
+	SystemDestroyer
+}
 
-		filter := w.systemFilters[st]
-		if len(filter) == 0 {
-			continue
-		}
+// systemEntry contains the system added to the world and its state.
+type systemEntry struct {
+	system  System
+	filters []*filter
+	tick    uint64 // the tick of the previous update
 
-		for fid, f := range filter {
-			if hasComponentCount(f.Exclude) > 0 {
-				w.systemCacheDeleteEntityFromSystem(e, st, fid)
-				continue
-			}
+	variable    bool // updated once per SystemsUpdate in the fixed timestep mode
+	ignorePause bool // updated while the world is paused
 
-			if hasComponentCount(f.Include) == len(f.Include) {
-				if w.systemFiltersEntityCache[st] == nil {
-					w.systemFiltersEntityCache[st] = make(map[filterIndex][]Entity)
-				}
+	disabled   bool
+	conditions []func(w World) bool
+	every      uint64 // update every N-th time, 0 or 1 means every time
+	skipped    uint64 // the number of updates skipped since the last one by the every condition
+	nonEmpty   []SystemFilter
+	queries    []*query // the queries of the nonEmpty conditions
 
-				w.systemFiltersEntityCache[st][fid] = appendIfMissing(w.systemFiltersEntityCache[st][fid], e)
-				continue
-			}
+	stage  Stage
+	before []System
+	after  []System
 
-			w.systemCacheDeleteEntityFromSystem(e, st, fid)
-		}
-	}
+	commands *Commands // see World.SystemCommands
 }
 
-func (w *world) systemEntityCacheRebuildBySystem(systemType reflect.Type) {
-	filter := w.systemFilters[systemType]
-	if len(filter) == 0 {
-		return
+// systemMatches returns true if the system is the passed instance,
+// or has the same type if the pattern is a nil pointer, e.g. (*MoveSystem)(nil).
+func systemMatches(pattern, s System) bool {
+	pt := reflect.TypeOf(pattern)
+	if pt != reflect.TypeOf(s) {
+		return false
 	}
 
-	for fid, f := range filter {
-		excludeIDs := make(map[uint64]struct{}) // map[EntityID]struct{}
-		for _, ex := range f.Exclude {
-			if len(w.components[ex]) == 0 {
-				continue
-			}
-
-			for e := range w.components[ex] {
-				excludeIDs[e.ID()] = struct{}{}
-			}
-		}
-
-		includeIDs := make(map[uint64]int) // map[EntityID]count
-		for _, in := range f.Include {
-			if len(w.components[in]) == 0 {
-				// TODO add coverage
-				break // If there is not at least one component from include, there is no point in further checking.
-			}
-
-			for e := range w.components[in] {
-				if _, exist := excludeIDs[e.ID()]; exist {
-					continue
-				}
-
-				includeIDs[e.ID()]++
-
-				if includeIDs[e.ID()] != len(f.Include) {
-					continue
-				}
-
-				// Append if system includes count == entity component count
-				if w.systemFiltersEntityCache[systemType] == nil {
-					w.systemFiltersEntityCache[systemType] = make(map[filterIndex][]Entity)
-				}
-
-				w.systemFiltersEntityCache[systemType][fid] = appendIfMissing(w.systemFiltersEntityCache[systemType][fid], e)
-			}
-		}
+	pv := reflect.ValueOf(pattern)
+	if pv.Kind() == reflect.Ptr && pv.IsNil() {
+		return true
 	}
-}
 
-func appendIfMissing(slice []Entity, i Entity) []Entity {
-	for _, ele := range slice {
-		if ele == i {
-			return slice
-		}
-	}
-	return append(slice, i)
+	return pt.Comparable() && pattern == s
 }
@@ -55,8 +55,8 @@ func TestSystem_Filter(t *testing.T) {
 	})
 
 	t.Run("After convert entity 2", func(t *testing.T) {
-		e2.Delete((*Component2)(nil))
 		e2.Replace(&Component1{Num: 1234})
+		e2.Delete((*Component2)(nil))
 
 		w.SystemsUpdate(time.Second)
 		require.Len(t, s1.Filtered, 1)
@@ -126,8 +126,6 @@ func TestSystem_Filter(t *testing.T) {
 		w.SystemsUpdate(time.Second)
 
 		require.Len(t, w.(*world).systems, 2)
-		require.Len(t, w.(*world).systemFiltersEntityCache, 2)
-		require.Len(t, w.(*world).systemFilters, 2)
 	})
 
 	w.RemoveSystem((*Component1System)(nil))
@@ -137,16 +135,14 @@ func TestSystem_Filter(t *testing.T) {
 		w.SystemsUpdate(time.Second)
 
 		require.Len(t, w.(*world).systems, 0)
-		require.Len(t, w.(*world).systemFiltersEntityCache, 0)
-		require.Len(t, w.(*world).systemFilters, 0)
 	})
 
 	s1or2 := &Component1Or2System{}
 	w.AddSystem(s1or2)
 
 	// revert entity 2
-	e2.Delete((*Component1)(nil))
 	e2.Replace(&Component2{Text: "Hello world"})
+	e2.Delete((*Component1)(nil))
 
 	t.Run("Add system 1Or2", func(t *testing.T) {
 		w.SystemsUpdate(time.Second)
@@ -202,7 +198,7 @@ func TestSystem_Filter(t *testing.T) {
 	})
 }
 
-var _ System = (*Component1System)(nil)
+var _ SystemUpdater = (*Component1System)(nil)
 
 type Component1System struct {
 	Filtered [][]Entity
@@ -210,7 +206,7 @@ type Component1System struct {
 
 func (s *Component1System) GetFilters() []SystemFilter {
 	return []SystemFilter{
-		{[]Component{(*Component1)(nil)}, []Component{(*Component2)(nil)}},
+		{Include: []Component{(*Component1)(nil)}, Exclude: []Component{(*Component2)(nil)}},
 	}
 }
 
@@ -224,7 +220,7 @@ func (s *Component1System) Update(_ time.Duration, filtered [][]Entity) {
 	}
 }
 
-var _ System = (*Component2System)(nil)
+var _ SystemUpdater = (*Component2System)(nil)
 
 type Component2System struct {
 	Filtered [][]Entity
@@ -232,7 +228,7 @@ type Component2System struct {
 
 func (s *Component2System) GetFilters() []SystemFilter {
 	return []SystemFilter{
-		{[]Component{(*Component2)(nil)}, []Component{(*Component1)(nil)}},
+		{Include: []Component{(*Component2)(nil)}, Exclude: []Component{(*Component1)(nil)}},
 	}
 }
 
@@ -246,7 +242,7 @@ func (s *Component2System) Update(_ time.Duration, filtered [][]Entity) {
 	}
 }
 
-var _ System = (*Component1And2System)(nil)
+var _ SystemUpdater = (*Component1And2System)(nil)
 
 type Component1And2System struct {
 	Filtered [][]Entity
@@ -254,7 +250,7 @@ type Component1And2System struct {
 
 func (s *Component1And2System) GetFilters() []SystemFilter {
 	return []SystemFilter{
-		{[]Component{(*Component1)(nil), (*Component2)(nil)}, nil},
+		{Include: []Component{(*Component1)(nil), (*Component2)(nil)}},
 	}
 }
 
@@ -268,7 +264,7 @@ func (s *Component1And2System) Update(_ time.Duration, filtered [][]Entity) {
 	}
 }
 
-var _ System = (*Component1Or2System)(nil)
+var _ SystemUpdater = (*Component1Or2System)(nil)
 
 type Component1Or2System struct {
 	Filtered [][]Entity
@@ -276,9 +272,9 @@ type Component1Or2System struct {
 
 func (s *Component1Or2System) GetFilters() []SystemFilter {
 	return []SystemFilter{
-		{[]Component{(*Component1)(nil)}, []Component{(*Component2)(nil)}},
-		{[]Component{(*Component2)(nil)}, []Component{(*Component1)(nil)}},
-		{[]Component{(*Component1)(nil), (*Component2)(nil)}, nil},
+		{Include: []Component{(*Component1)(nil)}, Exclude: []Component{(*Component2)(nil)}},
+		{Include: []Component{(*Component2)(nil)}, Exclude: []Component{(*Component1)(nil)}},
+		{Include: []Component{(*Component1)(nil), (*Component2)(nil)}},
 	}
 }
 
@@ -295,7 +291,7 @@ func (s *Component1Or2System) Update(_ time.Duration, filtered [][]Entity) {
 	}
 }
 
-var _ System = (*WithoutFilterSystem)(nil)
+var _ SystemUpdater = (*WithoutFilterSystem)(nil)
 
 type WithoutFilterSystem struct {
 	Filtered [][]Entity
@@ -309,7 +305,7 @@ func (s *WithoutFilterSystem) Update(_ time.Duration, filtered [][]Entity) {
 	s.Filtered = filtered
 }
 
-var _ System = (*WithEmptyFilterSystem)(nil)
+var _ SystemUpdater = (*WithEmptyFilterSystem)(nil)
 
 type WithEmptyFilterSystem struct {
 	Filtered [][]Entity
@@ -323,7 +319,7 @@ func (s *WithEmptyFilterSystem) Update(_ time.Duration, filtered [][]Entity) {
 	s.Filtered = filtered
 }
 
-var _ System = (*WithNilFilterSystem)(nil)
+var _ SystemUpdater = (*WithNilFilterSystem)(nil)
 
 type WithNilFilterSystem struct {
 	Filtered [][]Entity
@@ -338,3 +334,128 @@ func (s *WithNilFilterSystem) GetFilters() []SystemFilter {
 func (s *WithNilFilterSystem) Update(_ time.Duration, filtered [][]Entity) {
 	s.Filtered = filtered
 }
+
+func TestSystem_UpdateRows(t *testing.T) {
+	w := NewWorld()
+	s := &RowsSystem{}
+	w.AddSystem(s)
+
+	e1 := w.NewEntity()
+	c1 := e1.Get(&Component1{Num: 1})
+
+	e2 := w.NewEntity()
+	c2 := e2.Get(&Component1{Num: 2})
+	c3 := e2.Get(&Component3{Flag: true})
+
+	e3 := w.NewEntity()
+	e3.Get(&Component3{Flag: true})
+
+	w.SystemsUpdate(time.Second)
+	require.Len(t, s.Rows, 1)
+
+	rows := s.Rows[0]
+	require.Len(t, rows, 2)
+
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].Entity.ID() < rows[j].Entity.ID()
+	})
+
+	require.Equal(t, e1, rows[0].Entity)
+	require.Equal(t, []Component{c1, nil}, rows[0].Components)
+	require.Equal(t, c1, ComponentAt[*Component1](rows[0], 0))
+	require.Nil(t, ComponentAt[*Component3](rows[0], 1))
+
+	require.Equal(t, e2, rows[1].Entity)
+	require.Equal(t, []Component{c2, c3}, rows[1].Components)
+	require.Equal(t, c3, ComponentAt[*Component3](rows[1], 1))
+
+	t.Run("Rows reflect replaced component", func(t *testing.T) {
+		c := &Component1{Num: 42}
+		e1.Replace(c)
+		e2.Delete((*Component3)(nil))
+
+		w.SystemsUpdate(time.Second)
+		rows := s.Rows[0]
+		require.Len(t, rows, 2)
+		for _, r := range rows {
+			require.Nil(t, r.Components[1])
+			if r.Entity == e1 {
+				require.Equal(t, c, r.Components[0])
+			}
+		}
+	})
+}
+
+var _ SystemRowsUpdater = (*RowsSystem)(nil)
+
+type RowsSystem struct {
+	Rows [][]Row
+}
+
+func (s *RowsSystem) GetFilters() []SystemFilter {
+	return []SystemFilter{
+		{Include: []Component{(*Component1)(nil)}, Optional: []Component{(*Component3)(nil)}},
+	}
+}
+
+func (s *RowsSystem) UpdateRows(_ time.Duration, rows [][]Row) {
+	s.Rows = rows
+}
+
+func TestSystem_Instances(t *testing.T) {
+	w := NewWorld().(*world)
+
+	var order []string
+	record := func(name string) func() {
+		return func() { order = append(order, name) }
+	}
+
+	s1 := &AccessSystem{OnUpdate: record("s1")}
+	s2 := &AccessSystem{OnUpdate: record("s2")}
+	s3 := &AccessSystem{OnUpdate: record("s3")}
+	w.AddSystem(s1, After(s2))
+	w.AddSystem(s2)
+	w.AddSystem(s3)
+	require.Len(t, w.systems, 3, "Systems of the same type should not replace each other")
+
+	w.SystemsUpdate(time.Second)
+	require.Equal(t, []string{"s2", "s1", "s3"}, order)
+
+	t.Run("Re-add the same instance", func(t *testing.T) {
+		w.AddSystem(s2)
+		require.Len(t, w.systems, 3)
+	})
+
+	t.Run("Remove instance", func(t *testing.T) {
+		w.RemoveSystem(s1)
+		require.Len(t, w.systems, 2)
+
+		order = nil
+		w.SystemsUpdate(time.Second)
+		require.Equal(t, []string{"s2", "s3"}, order)
+	})
+
+	t.Run("Remove by type", func(t *testing.T) {
+		w.RemoveSystem((*AccessSystem)(nil))
+		require.Len(t, w.systems, 0)
+	})
+}
+
+func TestSystem_WithoutUpdater(t *testing.T) {
+	w := NewWorld()
+
+	require.PanicsWithValue(t,
+		"gecs: *gecs.WrongUpdateSystem doesn't implement SystemUpdater, SystemTryUpdater or SystemRowsUpdater",
+		func() { w.AddSystem(&WrongUpdateSystem{}) },
+	)
+	require.NoError(t, w.SystemsInit())
+}
+
+// WrongUpdateSystem has the Update method with a wrong signature.
+type WrongUpdateSystem struct{}
+
+func (s *WrongUpdateSystem) GetFilters() []SystemFilter {
+	return nil
+}
+
+func (s *WrongUpdateSystem) Update(_ float32, _ [][]Entity) {}
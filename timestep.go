@@ -0,0 +1,49 @@
+package gecs
+
+import (
+	"time"
+)
+
+// VariableRate makes the system update once per World.SystemsUpdate call with the passed delta
+// in the fixed timestep mode, e.g. for rendering. Without the fixed timestep mode it has no effect.
+func VariableRate() SystemOption {
+	return func(e *systemEntry) {
+		e.variable = true
+	}
+}
+
+func allSystems(*systemEntry) bool {
+	return true
+}
+
+func fixedRateSystems(e *systemEntry) bool {
+	return !e.variable
+}
+
+func variableRateSystems(e *systemEntry) bool {
+	return e.variable
+}
+
+// systemsUpdateFixed updates the fixed rate systems for every accumulated step, then the variable rate systems.
+func (w *world) systemsUpdateFixed(delta time.Duration) {
+	w.accumulator += delta
+
+	for steps := 0; w.accumulator >= w.fixedStep && steps < w.fixedMaxSteps && w.updateErr == nil; steps++ {
+		w.systemsUpdatePass(w.fixedStep, fixedRateSystems)
+		w.accumulator -= w.fixedStep
+		w.stepEvents()
+	}
+
+	// Catch-up limit is reached, the remaining whole steps are dropped.
+	w.accumulator %= w.fixedStep
+
+	w.systemsUpdatePass(delta, variableRateSystems)
+}
+
+func (w *world) Alpha() float64 {
+	if w.fixedStep <= 0 {
+		return 0
+	}
+
+	return float64(w.accumulator) / float64(w.fixedStep)
+}
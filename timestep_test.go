@@ -0,0 +1,73 @@
+package gecs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimestep_Fixed(t *testing.T) {
+	w := NewWorld(WithFixedTimestep(10*time.Millisecond, 5))
+
+	fixed := &AccessSystem{}
+	variable := &AccessSystem2{}
+	w.AddSystem(fixed)
+	w.AddSystem(variable, VariableRate())
+
+	w.SystemsUpdate(25 * time.Millisecond)
+	require.Equal(t, 2, fixed.Updates)
+	require.Equal(t, 10*time.Millisecond, fixed.Delta)
+	require.Equal(t, 1, variable.Updates)
+	require.Equal(t, 25*time.Millisecond, variable.Delta)
+	require.InDelta(t, 0.5, w.Alpha(), 1e-9)
+
+	t.Run("Accumulated time is used", func(t *testing.T) {
+		w.SystemsUpdate(5 * time.Millisecond)
+		require.Equal(t, 3, fixed.Updates)
+		require.Equal(t, 2, variable.Updates)
+		require.InDelta(t, 0, w.Alpha(), 1e-9)
+	})
+
+	t.Run("No fixed update", func(t *testing.T) {
+		w.SystemsUpdate(time.Millisecond)
+		require.Equal(t, 3, fixed.Updates)
+		require.Equal(t, 3, variable.Updates)
+		require.InDelta(t, 0.1, w.Alpha(), 1e-9)
+	})
+
+	t.Run("Catch-up limit", func(t *testing.T) {
+		w.SystemsUpdate(time.Second + 2*time.Millisecond)
+		require.Equal(t, 8, fixed.Updates)
+		require.Equal(t, 4, variable.Updates)
+		require.InDelta(t, 0.3, w.Alpha(), 1e-9, "The dropped steps should not affect alpha")
+	})
+}
+
+func TestTimestep_Disabled(t *testing.T) {
+	w := NewWorld()
+
+	s1 := &AccessSystem{}
+	s2 := &AccessSystem2{}
+	w.AddSystem(s1)
+	w.AddSystem(s2, VariableRate())
+
+	w.SystemsUpdate(25 * time.Millisecond)
+	require.Equal(t, 1, s1.Updates)
+	require.Equal(t, 25*time.Millisecond, s1.Delta)
+	require.Equal(t, 1, s2.Updates)
+	require.Equal(t, 0.0, w.Alpha())
+}
+
+func TestTimestep_Parallel(t *testing.T) {
+	w := NewWorld(WithFixedTimestep(10*time.Millisecond, 5), WithParallelSystems())
+
+	fixed := &AccessSystem{Acc: SystemAccess{Write: []Component{(*Component1)(nil)}}}
+	variable := &AccessSystem2{AccessSystem{Acc: SystemAccess{Read: []Component{(*Component2)(nil)}}}}
+	w.AddSystem(fixed)
+	w.AddSystem(variable, VariableRate())
+
+	w.SystemsUpdate(30 * time.Millisecond)
+	require.Equal(t, 3, fixed.Updates)
+	require.Equal(t, 1, variable.Updates)
+}
@@ -0,0 +1,113 @@
+package gecs
+
+import (
+	"math"
+	"time"
+)
+
+// Transform is the 2D transform: the translation, the rotation in radians and the scale.
+// Use NewTransform to create the transform with the unit scale, the zero scale collapses the entity to a point.
+type Transform struct {
+	X, Y           float64
+	Rotation       float64
+	ScaleX, ScaleY float64
+}
+
+// NewTransform returns the transform with the translation, no rotation and the unit scale.
+func NewTransform(x, y float64) Transform {
+	return Transform{X: x, Y: y, ScaleX: 1, ScaleY: 1}
+}
+
+// Mul returns the child transform relative to t converted to the space t is relative to.
+// The translation of the child is scaled and rotated by t, the rotations are added and the scales are multiplied.
+func (t Transform) Mul(child Transform) Transform {
+	sin, cos := math.Sincos(t.Rotation)
+	x, y := child.X*t.ScaleX, child.Y*t.ScaleY
+
+	return Transform{
+		X:        t.X + x*cos - y*sin,
+		Y:        t.Y + x*sin + y*cos,
+		Rotation: t.Rotation + child.Rotation,
+		ScaleX:   t.ScaleX * child.ScaleX,
+		ScaleY:   t.ScaleY * child.ScaleY,
+	}
+}
+
+// LocalTransform is the transform of the entity relative to its parent, see SetParent.
+type LocalTransform struct {
+	Transform
+}
+
+// WorldTransform is the transform of the entity in the world, computed from the LocalTransform by the system
+// returned by NewTransformSystem.
+type WorldTransform struct {
+	Transform
+}
+
+type transformSystem struct{}
+
+// NewTransformSystem returns a system that computes the WorldTransform of every entity with the LocalTransform,
+// combining it with the world transforms of its ancestors in parent-first order.
+// The entity whose parent has no LocalTransform is transformed as a root.
+//
+// The system is updated in StagePostUpdate, after the systems moving the entities.
+// The missing WorldTransform is added through World.SystemCommands, so it appears at the end of the stage.
+func NewTransformSystem() System {
+	return &transformSystem{}
+}
+
+func (s *transformSystem) GetFilters() []SystemFilter {
+	return []SystemFilter{
+		{Include: []Component{(*LocalTransform)(nil)}, Optional: []Component{(*WorldTransform)(nil)}},
+	}
+}
+
+func (s *transformSystem) Stage() Stage {
+	return StagePostUpdate
+}
+
+func (s *transformSystem) Access() SystemAccess {
+	return SystemAccess{
+		Read:  []Component{(*LocalTransform)(nil), (*ChildOf)(nil)},
+		Write: []Component{(*WorldTransform)(nil)},
+	}
+}
+
+func (s *transformSystem) Update(_ time.Duration, filtered [][]Entity) {
+	if len(filtered[0]) == 0 {
+		return
+	}
+
+	cmds := filtered[0][0].(*entity).w.SystemCommands(s)
+	transforms := make(map[Entity]Transform, len(filtered[0]))
+
+	var worldTransform func(e Entity) Transform
+	worldTransform = func(e Entity) Transform {
+		if t, ok := transforms[e]; ok {
+			return t
+		}
+
+		t := Get[*LocalTransform](e).Transform
+		if p := Parent(e); p != nil && p.Has((*LocalTransform)(nil)) {
+			t = worldTransform(p).Mul(t)
+		}
+
+		transforms[e] = t
+		return t
+	}
+
+	for _, e := range filtered[0] {
+		t := worldTransform(e)
+
+		wt, ok := TryGet[*WorldTransform](e)
+		if !ok {
+			cmds.Add(e, &WorldTransform{Transform: t})
+			continue
+		}
+
+		if wt.Transform != t {
+			wt.Transform = t
+			e.MarkChanged(wt)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package gecs
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransform_Mul(t *testing.T) {
+	parent := Transform{X: 10, Y: 20, Rotation: math.Pi / 2, ScaleX: 2, ScaleY: 2}
+	got := parent.Mul(Transform{X: 1, Y: 0, Rotation: 0.5, ScaleX: 3, ScaleY: 1})
+
+	require.InDelta(t, 10, got.X, 1e-9)
+	require.InDelta(t, 22, got.Y, 1e-9)
+	require.InDelta(t, math.Pi/2+0.5, got.Rotation, 1e-9)
+	require.Equal(t, 6.0, got.ScaleX)
+	require.Equal(t, 2.0, got.ScaleY)
+
+	require.Equal(t, parent, NewTransform(0, 0).Mul(parent))
+	require.Equal(t, parent, parent.Mul(NewTransform(0, 0)))
+}
+
+func TestTransform_System(t *testing.T) {
+	w := NewWorld()
+	w.AddSystem(NewTransformSystem())
+
+	vehicle := w.NewEntity()
+	vehicle.Get(&LocalTransform{NewTransform(100, 50)})
+
+	turret := w.NewEntity()
+	turret.Get(&LocalTransform{NewTransform(5, 0)})
+
+	barrel := w.NewEntity()
+	barrel.Get(&LocalTransform{NewTransform(2, 0)})
+
+	// The children are created before the parents, so the order of the entities is not parent-first.
+	SetParent(turret, vehicle)
+	SetParent(barrel, turret)
+
+	unmounted := w.NewEntity()
+	unmounted.Get(&LocalTransform{NewTransform(1, 1)})
+	SetParent(unmounted, w.NewEntity())
+
+	require.NoError(t, w.SystemsUpdate(time.Second))
+	require.Equal(t, NewTransform(100, 50), Get[*WorldTransform](vehicle).Transform)
+	require.Equal(t, NewTransform(105, 50), Get[*WorldTransform](turret).Transform)
+	require.Equal(t, NewTransform(107, 50), Get[*WorldTransform](barrel).Transform)
+	require.Equal(t, NewTransform(1, 1), Get[*WorldTransform](unmounted).Transform, "the parent without transform is ignored")
+
+	t.Run("Propagates the parent changes", func(t *testing.T) {
+		Get[*LocalTransform](vehicle).X = 0
+		Get[*LocalTransform](turret).Rotation = math.Pi
+
+		require.NoError(t, w.SystemsUpdate(time.Second))
+
+		wt := Get[*WorldTransform](barrel).Transform
+		require.InDelta(t, 3, wt.X, 1e-9)
+		require.InDelta(t, 50, wt.Y, 1e-9)
+		require.InDelta(t, math.Pi, wt.Rotation, 1e-9)
+	})
+
+	t.Run("Marks changed", func(t *testing.T) {
+		q := w.Query(SystemFilter{Changed: []Component{(*WorldTransform)(nil)}})
+		defer q.Close()
+		q.Entities()
+
+		Get[*LocalTransform](turret).Y = 1
+		require.NoError(t, w.SystemsUpdate(time.Second))
+		require.ElementsMatch(t, []Entity{turret, barrel}, q.Entities())
+	})
+}
@@ -1,125 +1,409 @@
 package gecs
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// ErrWorldStarted is returned by World.Run if the world is already running.
+var ErrWorldStarted = errors.New("world is already running")
+
 // World ecs interface.
 type World interface {
 	NewEntity() Entity
-
-	AddSystem(s System)
+	// Entity returns the alive entity by its ID.
+	Entity(id uint64) (Entity, bool)
+	// Entities returns all alive entities.
+	Entities() []Entity
+	// EntityCount returns the number of alive entities.
+	EntityCount() int
+
+	// Commands returns the world command buffer, which is applied at the end of every stage and after the update.
+	// The systems should use it to make structural changes without the exclusive access.
+	// The order of the commands pushed by the systems updated in parallel depends on the goroutine scheduling,
+	// such systems should use SystemCommands instead.
+	Commands() *Commands
+	// SystemCommands returns the command buffer of the system, or the world buffer if the system is not added.
+	// The system buffers are applied after the world buffer in the systems order,
+	// so the result doesn't depend on the parallel update.
+	SystemCommands(s System) *Commands
+	// Inbox returns the command buffer, which is applied at the start of the next SystemsUpdate.
+	// Use it to queue the changes from other goroutines.
+	Inbox() *Commands
+
+	// SetResource sets the world resource, a unique instance of its type, e.g. the window size or the random source.
+	// The resource is replaced if the world already has a resource of the same type. Use Resource to get it.
+	SetResource(r interface{})
+	// RemoveResource removes the resource with the type of the passed one, which can be a nil pointer.
+	RemoveResource(r interface{})
+
+	// Query returns a live entity set matching the filter, which can be used outside the systems.
+	Query(f SystemFilter) Query
+
+	// OnAdd registers the observer called after the component with the passed type is added to an entity.
+	OnAdd(c Component, fn ComponentObserver)
+	// OnReplace registers the observer called after the component with the passed type is replaced on an entity.
+	OnReplace(c Component, fn ComponentObserver)
+	// OnRemove registers the observer called before the component with the passed type is deleted from an entity,
+	// including the entity destruction.
+	OnRemove(c Component, fn ComponentObserver)
+
+	// AddSystem adds the system to the world. The systems are identified by instance,
+	// so several systems of the same type can be added. If the same system is already added,
+	// it is replaced keeping its position.
+	// Panics if the system implements none of SystemUpdater, SystemTryUpdater and SystemRowsUpdater,
+	// e.g. because of a wrong Update signature, since such a system would never be updated.
+	AddSystem(s System, opts ...SystemOption)
+	// RemoveSystem removes the system. If the passed system is a nil pointer, e.g. (*MoveSystem)(nil),
+	// all systems of its type are removed.
 	RemoveSystem(s System)
 
+	// SetSystemEnabled enables or disables the system, or all systems of its type like RemoveSystem.
+	// The disabled system is not updated, but its filters are kept up to date.
+	// The changes made while the system is disabled are not seen by it.
+	SetSystemEnabled(s System, enabled bool)
+
+	// SystemsInit resolves the systems order and calls Init on the systems in that order.
 	SystemsInit() error
 	// SystemsUpdate calls an update on all systems. Takes in the time elapsed from the previous call.
-	SystemsUpdate(delta time.Duration)
+	// Returns the error of SystemTryUpdater.TryUpdate with the ErrorPolicyStop policy,
+	// in that case the remaining systems are not updated in this call.
+	// In the fixed timestep mode, the fixed rate systems are updated zero or more times with the fixed delta,
+	// then the variable rate systems are updated once with the passed delta.
+	SystemsUpdate(delta time.Duration) error
+	// Pause pauses the systems update, except the systems added with the IgnorePause option.
+	Pause()
+	// Resume resumes the paused systems update.
+	Resume()
+	// Paused returns true if the world is paused.
+	Paused() bool
+	// Step makes the next SystemsUpdate call update all systems once while the world is paused.
+	// In the fixed timestep mode, the fixed rate systems are updated with exactly one step.
+	Step()
+	// SetTimeScale sets the multiplier applied to the delta passed to SystemsUpdate, 1 by default.
+	// Values less than 1 slow the simulation down, greater than 1 speed it up. Negative values are treated as 0.
+	SetTimeScale(scale float64)
+	// TimeScale returns the current time scale.
+	TimeScale() float64
+	// Alpha returns the interpolation factor in the range [0, 1) between the two last fixed updates
+	// for the variable rate systems. Always 0 without the fixed timestep mode.
+	Alpha() float64
 	SystemsDestroy()
 
 	// Run calls the Update method with a TPS (Tick per second) rate. Blocking method!
+	// Returns the SystemsUpdate error.
 	Run(tps uint) error
+	// RunContext is the same as Run, but also returns when the context is done.
+	// SystemsDestroy is called on return, even if a system panics. The world can be run again after Run returns,
+	// the systems are initialized again then.
+	RunContext(ctx context.Context, tps uint) error
+	// Stop makes the running or next Run return. Can be called any number of times from any goroutine.
 	Stop()
+	// Done returns a channel that is closed when the running or last Run returns.
+	Done() <-chan struct{}
 }
 
 // NewWorld creates new ecs world instance.
-func NewWorld() World {
-	return &world{
-		entityID:   0,
-		entities:   nil,
-		components: make(map[componentType]map[Entity]Component),
+func NewWorld(opts ...WorldOption) World {
+	w := &world{
+		entitySlots: make([]entitySlot, 1), // Slot 0 is reserved, so the ID of an entity is never zero.
+
+		componentIDs:   make(map[componentKey]componentID),
+		pairTargets:    make(map[uint64]*targetPairs),
+		archetypeIndex: make(map[string]*archetype),
+
+		systems: nil,
 
-		systems:                  nil,
-		systemFilters:            make(map[systemType][]systemFilterTypes),
-		systemFiltersEntityCache: make(map[systemType]map[filterIndex][]Entity),
+		timeScale: math.Float64bits(1),
+
+		tick:           1,
+		removed:        make(map[componentID][]removedComponent),
+		removedTracked: make(map[componentID]bool),
+
+		observers: make(map[componentID]*componentObservers),
+		commands:  &Commands{},
+		inbox:     &Commands{},
+		events:    make(map[reflect.Type]eventsUpdater),
+		resources: make(map[reflect.Type]interface{}),
+
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
 
-		done: make(chan struct{}, 1),
+	for _, opt := range opts {
+		opt(w)
 	}
+
+	w.root = w.archetypeByIDs(nil)
+	return w
 }
 
 // Type aliases for better readability.
 type componentType = reflect.Type
-type systemType = reflect.Type
-type filterIndex = int
+
+// entitySlot is a slot of the entity registry.
+// The generation is increased every time the slot is freed, so the handles of destroyed entities become stale.
+type entitySlot struct {
+	entity     *entity // nil if the slot is free
+	generation uint32
+}
 
 type world struct {
-	entityID uint64
-	entities []Entity
+	entitySlots []entitySlot
+	freeSlots   []uint32
+
+	componentIDs  map[componentKey]componentID
+	componentKeys []componentKey          // [componentID]componentKey of the component types
+	pairKeys      []componentKey          // [-componentID-1]componentKey of the pairs, see newPairID
+	freePairIDs   []componentID           // the ids of the pairs of the destroyed targets for reuse
+	pairTargets   map[uint64]*targetPairs // the pairs by the target entity ID
+
+	archetypes     []*archetype
+	dropped        int                   // the number of the dropped archetypes in archetypes and the filters
+	archetypeIndex map[string]*archetype // map[archetype.key]*archetype
+	root           *archetype            // archetype without components
+
+	systems        []*systemEntry // in the order they were added
+	systemOrder    []*systemEntry // cached systemsOrder result
+	systemOrderErr error
+	queries        []*query
+	schedule       [][]*systemEntry // cached systemBatches result
+
+	parallel bool
+
+	errorPolicy  ErrorPolicy
+	updateErr    error // the error stopping the current update
+	panicHandler PanicHandler
+	accessed     atomic.Value // *entity, the last entity accessed by the systems
+
+	paused    int32  // accessed atomically
+	stepping  int32  // accessed atomically, set by Step
+	timeScale uint64 // accessed atomically, math.Float64bits of the scale
+
+	fixedStep     time.Duration
+	fixedMaxSteps int
+	accumulator   time.Duration // the time not simulated by the fixed updates yet
+
+	tick           uint64
+	updating       bool // SystemsUpdate is in progress
+	removed        map[componentID][]removedComponent
+	removedTracked map[componentID]bool
+
+	observers map[componentID]*componentObservers
+	commands  *Commands
+	inbox     *Commands
+	events    map[reflect.Type]eventsUpdater
+	resources map[reflect.Type]interface{}
+
+	locking          bool
+	mu               sync.Mutex
+	pendingObservers []observerCall // the observers calls deferred until the world is unlocked
+
+	autoDestroy bool
+
+	runMu   sync.Mutex
+	running bool
+	stop    chan struct{} // closed by Stop, replaced when Run returns
+	done    chan struct{} // closed when Run returns, replaced when the next Run starts
+}
 
-	components map[componentType]map[Entity]Component
+func (w *world) NewEntity() Entity {
+	w.lock()
+	defer w.unlock()
+
+	var index uint32
+	if n := len(w.freeSlots); n > 0 {
+		index = w.freeSlots[n-1]
+		w.freeSlots = w.freeSlots[:n-1]
+	} else {
+		index = uint32(len(w.entitySlots))
+		w.entitySlots = append(w.entitySlots, entitySlot{})
+	}
 
-	systems                  []System
-	systemFilters            map[systemType][]systemFilterTypes
-	systemFiltersEntityCache map[systemType]map[filterIndex][]Entity
+	slot := &w.entitySlots[index]
+	e := &entity{w: w, id: entityID(index, slot.generation)}
+	slot.entity = e
+	w.moveEntity(e, w.root)
 
-	done   chan struct{}
-	ticker *time.Ticker
+	return e
 }
 
-func (w *world) NewEntity() Entity {
-	w.entityID++
-	e := &entity{w: w, id: w.entityID}
+func (w *world) Entity(id uint64) (Entity, bool) {
+	w.lock()
+	defer w.unlock()
 
-	w.entities = append(w.entities, e)
-	return e
+	e := w.entity(id)
+	if e == nil {
+		return nil, false
+	}
+
+	return e, true
 }
 
-func (w *world) AddSystem(s System) {
-	w.RemoveSystem(s)
+// entity returns the alive entity with the ID, or nil.
+func (w *world) entity(id uint64) *entity {
+	index := entityIndex(id)
+	if index == 0 || int(index) >= len(w.entitySlots) {
+		return nil
+	}
 
-	w.systems = append(w.systems, s)
+	e := w.entitySlots[index].entity
+	if e == nil || e.id != id {
+		return nil
+	}
 
-	st := reflect.TypeOf(s)
+	return e
+}
 
-	for _, f := range s.GetFilters() {
-		var in, ex []reflect.Type
-		for _, v := range f.Include {
-			in = append(in, reflect.TypeOf(v))
-		}
-		for _, v := range f.Exclude {
-			ex = append(ex, reflect.TypeOf(v))
+func (w *world) Entities() []Entity {
+	w.lock()
+	defer w.unlock()
+
+	es := make([]Entity, 0, w.entityCount())
+	for _, slot := range w.entitySlots {
+		if slot.entity != nil {
+			es = append(es, slot.entity)
 		}
+	}
+
+	return es
+}
 
-		w.systemFilters[st] = append(w.systemFilters[st], systemFilterTypes{
-			Include: in,
-			Exclude: ex,
-		})
+func (w *world) EntityCount() int {
+	w.lock()
+	defer w.unlock()
+
+	return w.entityCount()
+}
+
+func (w *world) entityCount() int {
+	return len(w.entitySlots) - 1 - len(w.freeSlots) // minus reserved slot
+}
+
+// entityAlive returns true if the entity handle is the current owner of its slot.
+func (w *world) entityAlive(e *entity) bool {
+	index := entityIndex(e.id)
+	return int(index) < len(w.entitySlots) && w.entitySlots[index].entity == e
+}
+
+// destroyEntity removes the entity from the world and removes the pairs targeting it from other entities.
+func (w *world) destroyEntity(e *entity) {
+	w.removeEntity(e)
+	w.freeEntity(e)
+	w.removeTargetPairs(e.id)
+}
+
+// freeEntity releases the entity slot for reuse and makes all handles of the entity stale.
+func (w *world) freeEntity(e *entity) {
+	index := entityIndex(e.id)
+	slot := &w.entitySlots[index]
+	slot.entity = nil
+	slot.generation++
+
+	w.freeSlots = append(w.freeSlots, index)
+}
+
+// entityID packs the slot index and generation into the entity ID.
+func entityID(index, generation uint32) uint64 {
+	return uint64(generation)<<32 | uint64(index)
+}
+
+// entityIndex returns the slot index from the entity ID.
+func entityIndex(id uint64) uint32 {
+	return uint32(id)
+}
+
+func (w *world) AddSystem(s System, opts ...SystemOption) {
+	switch s.(type) {
+	case SystemUpdater, SystemTryUpdater, SystemRowsUpdater:
+	default:
+		panic(fmt.Sprintf("gecs: %s doesn't implement SystemUpdater, SystemTryUpdater or SystemRowsUpdater", reflect.TypeOf(s)))
 	}
 
-	if len(w.components) == 0 {
-		return
+	e := &systemEntry{system: s, stage: StageUpdate, commands: &Commands{}}
+	if ss, ok := s.(SystemStager); ok {
+		e.stage = ss.Stage()
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	w.lock()
+	defer w.unlock()
+
+	for _, f := range s.GetFilters() {
+		e.filters = append(e.filters, w.newFilter(f))
+	}
+	for _, f := range e.nonEmpty {
+		e.queries = append(e.queries, w.query(f))
 	}
 
-	w.systemEntityCacheRebuildBySystem(st)
+	w.systemOrder = nil
+	w.schedule = nil
+
+	for i, ee := range w.systems {
+		if systemMatches(ee.system, s) {
+			ee.close()
+
+			// The replacement keeps the pending commands of the system.
+			e.commands = ee.commands
+			w.systems[i] = e
+			return
+		}
+	}
+
+	w.systems = append(w.systems, e)
 }
 
 func (w *world) RemoveSystem(s System) {
-	st := reflect.TypeOf(s)
+	w.lock()
+	defer w.unlock()
 
-	for i, ss := range w.systems {
-		sst := reflect.TypeOf(ss)
-		if st == sst {
-			w.systems = append(w.systems[:i], w.systems[i+1:]...)
-			break
+	systems := w.systems[:0]
+	for _, e := range w.systems {
+		if systemMatches(s, e.system) {
+			e.close()
+
+			// The pending commands of the removed system are still applied.
+			for _, cmd := range e.commands.take() {
+				w.commands.push(cmd)
+			}
+			continue
 		}
+
+		systems = append(systems, e)
+	}
+	for i := len(systems); i < len(w.systems); i++ {
+		w.systems[i] = nil
 	}
+	w.systems = systems
 
-	delete(w.systemFiltersEntityCache, st)
-	delete(w.systemFilters, st)
+	w.systemOrder = nil
+	w.schedule = nil
 }
 
 func (w *world) SystemsInit() error {
-	for _, s := range w.systems {
-		ss, ok := s.(SystemIniter)
+	order, err := w.systemsOrder()
+	if err != nil {
+		return err
+	}
+
+	for _, e := range order {
+		ss, ok := e.system.(SystemIniter)
 		if !ok {
 			continue
 		}
 
 		err := ss.Init()
 		if err != nil {
-			st := reflect.TypeOf(s)
+			st := reflect.TypeOf(e.system)
 
 			return fmt.Errorf("%s: %w", st.String(), err)
 		}
@@ -128,26 +412,145 @@ func (w *world) SystemsInit() error {
 	return nil
 }
 
-func (w *world) SystemsUpdate(delta time.Duration) {
-	for _, s := range w.systems {
-		st := reflect.TypeOf(s)
+func (w *world) SystemsUpdate(delta time.Duration) error {
+	w.inbox.apply(w)
 
-		var filteredEntities [][]Entity
-		for fid := range w.systemFilters[st] {
-			var entities []Entity
-			if len(w.systemFiltersEntityCache[st]) > 0 {
-				entities = w.systemFiltersEntityCache[st][fid]
-			}
-			filteredEntities = append(filteredEntities, entities)
+	w.lock()
+	w.updating = true
+	w.unlock()
+
+	defer func() {
+		w.lock()
+		w.updating = false
+		w.unlock()
+	}()
+
+	delta = w.scaleDelta(delta)
+
+	// The events are kept for the fixed steps only while they are running.
+	fixed := false
+
+	switch {
+	case !w.Paused():
+		if w.fixedStep > 0 {
+			fixed = delta > 0
+			w.systemsUpdateFixed(delta)
+		} else {
+			w.systemsUpdatePass(delta, allSystems)
+		}
+	case w.takeStep():
+		w.systemsStep(delta)
+	default:
+		w.systemsUpdatePass(delta, pauseIgnoringSystems)
+	}
+
+	w.pruneRemoved()
+	w.updateEvents(fixed)
+
+	err := w.updateErr
+	w.updateErr = nil
+	return err
+}
+
+// systemsUpdatePass updates the systems passing the selector and their run conditions in the resolved order.
+func (w *world) systemsUpdatePass(delta time.Duration, selector func(e *systemEntry) bool) {
+	selected := func(e *systemEntry) bool {
+		return selector(e) && w.systemShouldRun(e)
+	}
+
+	if w.parallel {
+		w.systemsUpdateParallel(delta, selected)
+		w.applyCommands()
+		return
+	}
+
+	// The order error is returned by SystemsInit, here the systems are updated in the fallback order.
+	order, _ := w.systemsOrder()
+	for i, e := range order {
+		if w.updateErr != nil {
+			break
+		}
+
+		// The stage sync point.
+		if i > 0 && e.stage != order[i-1].stage {
+			w.applyCommands()
 		}
 
-		s.Update(delta, filteredEntities)
+		if selected(e) {
+			w.systemUpdate(e, delta)
+		}
+	}
+
+	w.applyCommands()
+}
+
+// systemUpdate updates the system and advances the world tick.
+func (w *world) systemUpdate(e *systemEntry, delta time.Duration) {
+	w.lock()
+	since := e.tick
+	e.tick = w.tick
+	w.unlock()
+
+	err := w.systemUpdateSince(e, delta, since)
+	if err != nil {
+		w.systemError(e, err)
+	}
+
+	w.lock()
+	w.tick++
+	w.unlock()
+}
+
+// systemUpdateSince updates the system with the filtered entities changed after the since tick.
+// If the world has a panic handler, the system panic is recovered and returned as *SystemPanic.
+func (w *world) systemUpdateSince(e *systemEntry, delta time.Duration, since uint64) (err error) {
+	if w.panicHandler != nil {
+		w.accessed.Store((*entity)(nil))
+		defer w.recoverSystem(e, &err)
+	}
+
+	switch s := e.system.(type) {
+	case SystemRowsUpdater:
+		s.UpdateRows(delta, w.rowLists(e, since))
+	case SystemTryUpdater:
+		return s.TryUpdate(delta, w.entityLists(e, since))
+	case SystemUpdater:
+		s.Update(delta, w.entityLists(e, since))
+	}
+
+	return nil
+}
+
+// entityLists returns the entities of the system filters changed after the since tick.
+func (w *world) entityLists(e *systemEntry, since uint64) [][]Entity {
+	w.lock()
+	defer w.unlock()
+
+	var filteredEntities [][]Entity
+	for _, f := range e.filters {
+		filteredEntities = append(filteredEntities, f.entityList(since))
 	}
+
+	return filteredEntities
+}
+
+// rowLists returns the rows of the system filters changed after the since tick.
+func (w *world) rowLists(e *systemEntry, since uint64) [][]Row {
+	w.lock()
+	defer w.unlock()
+
+	var filteredRows [][]Row
+	for _, f := range e.filters {
+		filteredRows = append(filteredRows, f.rowList(since))
+	}
+
+	return filteredRows
 }
 
 func (w *world) SystemsDestroy() {
-	for _, s := range w.systems {
-		ss, ok := s.(SystemDestroyer)
+	order, _ := w.systemsOrder()
+	for _, e := range order {
+		ss, ok := e.system.(SystemDestroyer)
 		if !ok {
 			continue
 		}
@@ -156,43 +559,109 @@ func (w *world) SystemsDestroy() {
 	}
 }
 
-func (w *world) Run(fps uint) error {
-	if fps == 0 {
-		return errors.New("fps must be greater than 0")
+func (w *world) Run(tps uint) error {
+	return w.RunContext(context.Background(), tps)
+}
+
+func (w *world) RunContext(ctx context.Context, tps uint) error {
+	if tps == 0 {
+		return errors.New("tps must be greater than 0")
 	}
 
-	err := w.SystemsInit()
+	stop, err := w.startRun()
 	if err != nil {
 		return err
 	}
+	defer w.finishRun()
 
-	delay := time.Second / time.Duration(fps)
-	w.ticker = time.NewTicker(delay)
+	err = w.SystemsInit()
+	if err != nil {
+		return err
+	}
+	defer w.SystemsDestroy()
+
+	delay := time.Second / time.Duration(tps)
+	ticker := time.NewTicker(delay)
+	defer ticker.Stop()
 
 	last := time.Now()
 
-loop:
 	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-stop:
+			return nil
+		default:
+		}
+
 		delta := time.Since(last)
 		last = time.Now()
-		w.SystemsUpdate(delta)
+		err := w.SystemsUpdate(delta)
+		if err != nil {
+			return err
+		}
 
 		select {
-		case <-w.ticker.C:
-			continue
-		case <-w.done:
-			break loop
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		case <-stop:
+			return nil
 		}
 	}
+}
 
-	w.SystemsDestroy()
-	return nil
+// startRun marks the world running and returns the stop channel of the run.
+func (w *world) startRun() (chan struct{}, error) {
+	w.runMu.Lock()
+	defer w.runMu.Unlock()
+
+	if w.running {
+		return nil, ErrWorldStarted
+	}
+	w.running = true
+
+	select {
+	case <-w.done:
+		w.done = make(chan struct{})
+	default:
+	}
+
+	return w.stop, nil
+}
+
+// finishRun marks the world not running, so it can be run again, and closes the done channel.
+// The Stop of the finished run doesn't affect the next one.
+func (w *world) finishRun() {
+	w.runMu.Lock()
+	defer w.runMu.Unlock()
+
+	w.running = false
+
+	select {
+	case <-w.stop:
+		w.stop = make(chan struct{})
+	default:
+	}
+
+	close(w.done)
 }
 
 func (w *world) Stop() {
-	if w.ticker != nil {
-		w.ticker.Stop()
+	w.runMu.Lock()
+	defer w.runMu.Unlock()
+
+	select {
+	case <-w.stop:
+	default:
+		close(w.stop)
 	}
+}
+
+func (w *world) Done() <-chan struct{} {
+	w.runMu.Lock()
+	defer w.runMu.Unlock()
 
-	w.done <- struct{}{}
+	return w.done
 }
@@ -0,0 +1,216 @@
+package gecs
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorld_Entity(t *testing.T) {
+	w := NewWorld()
+	e1 := w.NewEntity()
+	e2 := w.NewEntity()
+
+	t.Run("Get by ID", func(t *testing.T) {
+		got, ok := w.Entity(e1.ID())
+		require.True(t, ok)
+		require.Equal(t, e1, got)
+
+		got, ok = w.Entity(e2.ID())
+		require.True(t, ok)
+		require.Equal(t, e2, got)
+	})
+
+	t.Run("Not exist ID", func(t *testing.T) {
+		_, ok := w.Entity(0)
+		require.False(t, ok)
+
+		_, ok = w.Entity(12345)
+		require.False(t, ok)
+	})
+
+	t.Run("Destroyed entity", func(t *testing.T) {
+		id := e1.ID()
+		e1.Destroy()
+
+		_, ok := w.Entity(id)
+		require.False(t, ok)
+
+		t.Run("Reused slot", func(t *testing.T) {
+			e3 := w.NewEntity()
+			require.Equal(t, entityIndex(id), entityIndex(e3.ID()))
+
+			_, ok = w.Entity(id)
+			require.False(t, ok, "Stale ID should not return the new entity")
+
+			got, ok := w.Entity(e3.ID())
+			require.True(t, ok)
+			require.Equal(t, e3, got)
+		})
+	})
+}
+
+func TestWorld_Entities(t *testing.T) {
+	w := NewWorld()
+	require.Len(t, w.Entities(), 0)
+	require.Equal(t, 0, w.EntityCount())
+
+	e1 := w.NewEntity()
+	e2 := w.NewEntity()
+	e3 := w.NewEntity()
+	require.Equal(t, []Entity{e1, e2, e3}, w.Entities())
+	require.Equal(t, 3, w.EntityCount())
+
+	e2.Destroy()
+	require.Equal(t, []Entity{e1, e3}, w.Entities())
+	require.Equal(t, 2, w.EntityCount())
+
+	// Destroy twice
+	e2.Destroy()
+	require.Equal(t, 2, w.EntityCount())
+
+	e4 := w.NewEntity()
+	require.Equal(t, []Entity{e1, e4, e3}, w.Entities())
+	require.Equal(t, 3, w.EntityCount())
+}
+
+func TestWorld_RunContext(t *testing.T) {
+	w := NewWorld()
+	s := &LifecycleSystem{}
+	w.AddSystem(s)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.OnUpdate = cancel
+
+	require.NoError(t, w.RunContext(ctx, 1000))
+	require.Equal(t, 1, s.Inits)
+	require.Equal(t, 1, s.Updates)
+	require.Equal(t, 1, s.Destroys)
+
+	select {
+	case <-w.Done():
+	default:
+		t.Fatal("Done should be closed after Run returns")
+	}
+
+	t.Run("Run again", func(t *testing.T) {
+		s.OnUpdate = w.Stop
+		require.NoError(t, w.Run(1000))
+		require.Equal(t, 2, s.Inits)
+		require.Equal(t, 2, s.Updates)
+		require.Equal(t, 2, s.Destroys)
+		<-w.Done()
+	})
+}
+
+func TestWorld_Stop(t *testing.T) {
+	w := NewWorld()
+	s := &LifecycleSystem{}
+	w.AddSystem(s)
+
+	// Stop doesn't block without Run and can be called many times.
+	w.Stop()
+	w.Stop()
+
+	require.NoError(t, w.Run(1000))
+	require.Equal(t, 0, s.Updates)
+	require.Equal(t, 1, s.Destroys)
+
+	t.Run("Stop running world", func(t *testing.T) {
+		w := NewWorld()
+		updated := make(chan struct{})
+		var once sync.Once
+		s := &LifecycleSystem{OnUpdate: func() { once.Do(func() { close(updated) }) }}
+		w.AddSystem(s)
+
+		errs := make(chan error, 1)
+		go func() {
+			errs <- w.Run(1000)
+		}()
+
+		<-updated
+		require.ErrorIs(t, w.Run(1000), ErrWorldStarted, "the running world can't be run concurrently")
+		w.Stop()
+		w.Stop()
+
+		select {
+		case <-w.Done():
+			require.NoError(t, <-errs)
+			require.Equal(t, 1, s.Destroys)
+		case <-time.After(time.Second):
+			t.Fatal("world is not stopped")
+		}
+	})
+}
+
+func TestWorld_RunPanic(t *testing.T) {
+	w := NewWorld()
+	s := &LifecycleSystem{OnUpdate: func() { panic("system panic") }}
+	w.AddSystem(s)
+
+	require.PanicsWithValue(t, "system panic", func() {
+		_ = w.Run(1000)
+	})
+	require.Equal(t, 1, s.Destroys, "SystemsDestroy should be called on panic")
+	<-w.Done()
+
+	t.Run("Parallel", func(t *testing.T) {
+		w := NewWorld(WithParallelSystems())
+		s1 := &AccessLifecycleSystem{
+			LifecycleSystem: LifecycleSystem{OnUpdate: func() { panic("system panic") }},
+			Acc:             SystemAccess{Write: []Component{(*Component1)(nil)}},
+		}
+		s2 := &AccessLifecycleSystem{Acc: SystemAccess{Write: []Component{(*Component2)(nil)}}}
+		w.AddSystem(s1)
+		w.AddSystem(s2)
+		require.Equal(t, [][]System{{s1, s2}}, batchSystems(w.(*world)))
+
+		require.PanicsWithValue(t, "system panic", func() {
+			_ = w.Run(1000)
+		})
+		require.Equal(t, 1, s1.Destroys, "SystemsDestroy should be called on panic")
+		require.Equal(t, 1, s2.Destroys, "SystemsDestroy should be called on panic")
+		<-w.Done()
+	})
+}
+
+type LifecycleSystem struct {
+	OnUpdate func()
+
+	Inits    int
+	Updates  int
+	Destroys int
+}
+
+func (s *LifecycleSystem) GetFilters() []SystemFilter {
+	return nil
+}
+
+func (s *LifecycleSystem) Init() error {
+	s.Inits++
+	return nil
+}
+
+func (s *LifecycleSystem) Update(time.Duration, [][]Entity) {
+	s.Updates++
+
+	if s.OnUpdate != nil {
+		s.OnUpdate()
+	}
+}
+
+func (s *LifecycleSystem) Destroy() {
+	s.Destroys++
+}
+
+type AccessLifecycleSystem struct {
+	LifecycleSystem
+	Acc SystemAccess
+}
+
+func (s *AccessLifecycleSystem) Access() SystemAccess {
+	return s.Acc
+}